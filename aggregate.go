@@ -0,0 +1,63 @@
+package supabase
+
+// Aggregate functions for Select. Columns selected next to an aggregate act
+// as its GROUP BY, so simple reports need no database view:
+//
+//	var rows []struct {
+//		Category string  `json:"category"`
+//		Total    float64 `json:"total"`
+//		Orders   int     `json:"orders"`
+//	}
+//	err := client.From("orders").
+//		Select("category", supabase.Sum("amount").As("total"), supabase.Count().As("orders")).
+//		Decode(ctx, &rows)
+//	// select=category,total:amount.sum(),orders:count()
+//
+// Aggregates are disabled in PostgREST by default; enable them with the
+// db-aggregates-enabled setting (pgrst.db_aggregates_enabled in Supabase).
+
+// Count counts the rows of each group.
+func Count() Column {
+	return "count()"
+}
+
+// CountOf counts the non-null values of column in each group.
+func CountOf(column Column) Column {
+	return aggregate(column, "count")
+}
+
+// Sum adds up column in each group.
+func Sum(column Column) Column {
+	return aggregate(column, "sum")
+}
+
+// Avg averages column in each group.
+func Avg(column Column) Column {
+	return aggregate(column, "avg")
+}
+
+// Min returns the smallest value of column in each group.
+func Min(column Column) Column {
+	return aggregate(column, "min")
+}
+
+// Max returns the largest value of column in each group.
+func Max(column Column) Column {
+	return aggregate(column, "max")
+}
+
+func aggregate(column Column, function string) Column {
+	return column + "." + Column(function) + "()"
+}
+
+// As renames the column in the result, e.g. Sum("amount").As("total")
+// renders total:amount.sum().
+func (c Column) As(alias string) Column {
+	return Column(alias) + ":" + c
+}
+
+// Cast converts the column to type in the result, e.g. Avg("rating").Cast("int")
+// renders rating.avg()::int.
+func (c Column) Cast(typ string) Column {
+	return c + "::" + Column(typ)
+}
@@ -0,0 +1,52 @@
+package supabase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAggregates(t *testing.T) {
+	tests := []struct {
+		column Column
+		want   string
+	}{
+		{Count(), "count()"},
+		{CountOf("id"), "id.count()"},
+		{Sum("amount").As("total"), "total:amount.sum()"},
+		{Avg("rating").Cast("int"), "rating.avg()::int"},
+		{Min(JSONText("data", "price")), "data->>price.min()"},
+		{Max("created_at").As("latest"), "latest:created_at.max()"},
+	}
+	for _, tt := range tests {
+		if string(tt.column) != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, tt.column)
+		}
+	}
+}
+
+func TestAggregateDecode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query().Get("select"), "category,total:amount.sum(),orders:count()"; got != want {
+			t.Errorf("Expected select %s, got %s", want, got)
+		}
+		w.Write([]byte(`[{"category":"books","total":42.5,"orders":3}]`))
+	}))
+	defer server.Close()
+
+	var rows []struct {
+		Category string  `json:"category"`
+		Total    float64 `json:"total"`
+		Orders   int     `json:"orders"`
+	}
+	err := NewClient(server.URL, "key", "token").From("orders").
+		Select("category", Sum("amount").As("total"), Count().As("orders")).
+		Decode(context.Background(), &rows)
+	if err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Total != 42.5 || rows[0].Orders != 3 {
+		t.Errorf("Unexpected rows %+v", rows)
+	}
+}
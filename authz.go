@@ -0,0 +1,139 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrForbidden is returned by RequireRole when a token's claims do not grant
+// access.
+var ErrForbidden = errors.New("supabase: forbidden")
+
+// Claim returns the claim at path, a dot-separated path into nested objects
+// such as "app_metadata.tier".
+func (tc *TokenClaims) Claim(path string) (any, bool) {
+	var v any = tc.Raw
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// RequireRole returns nil if the token's role claim is one of roles, and an
+// error wrapping ErrForbidden otherwise.
+func RequireRole(claims *TokenClaims, roles ...string) error {
+	if claims != nil && slices.Contains(roles, claims.Role) {
+		return nil
+	}
+	return fmt.Errorf("%w: role %q not in %v", ErrForbidden, claims.roleOrEmpty(), roles)
+}
+
+func (tc *TokenClaims) roleOrEmpty() string {
+	if tc == nil {
+		return ""
+	}
+	return tc.Role
+}
+
+// HasClaim reports whether the claim at path equals value, or contains it
+// when the claim is an array:
+//
+//	HasClaim(claims, "app_metadata.tier", "pro")
+//	HasClaim(claims, "app_metadata.roles", "admin")
+//
+// Values are compared by their JSON encoding, so 1 matches a claim of 1.0.
+func HasClaim(claims *TokenClaims, path string, value any) bool {
+	if claims == nil {
+		return false
+	}
+	claim, ok := claims.Claim(path)
+	if !ok {
+		return false
+	}
+	want, err := normalizeJSON(value)
+	if err != nil {
+		return false
+	}
+	if items, ok := claim.([]any); ok {
+		if _, wantArray := want.([]any); !wantArray {
+			return slices.ContainsFunc(items, func(item any) bool { return reflect.DeepEqual(item, want) })
+		}
+	}
+	return reflect.DeepEqual(claim, want)
+}
+
+// normalizeJSON converts v to the form encoding/json decodes it into.
+func normalizeJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+type claimsContextKey struct{}
+
+// ClaimsFromContext returns the claims stored by Authorize.
+func ClaimsFromContext(ctx context.Context) (*TokenClaims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*TokenClaims)
+	return claims, ok
+}
+
+// Authorize returns net/http middleware that verifies the Authorization
+// header with v and lets the request through only if check accepts the
+// claims. Missing or invalid tokens get 401 Unauthorized, rejected claims 403
+// Forbidden, and tokens that cannot be checked because the auth server is
+// unreachable or failing 503 Service Unavailable. The claims are stored in
+// the request context for ClaimsFromContext:
+//
+//	signedIn := supabase.Authorize(verifier, func(c *supabase.TokenClaims) error {
+//		return supabase.RequireRole(c, "authenticated")
+//	})
+//	mux.Handle("/account/", signedIn(accountHandler))
+//
+// A nil check accepts any valid token. Authorize is meant for user sessions:
+// HS256 tokens are verified through the auth server's /user endpoint, which
+// rejects tokens without a user, so the anon and service_role keys never
+// pass it and RequireRole(c, "service_role") cannot be satisfied through
+// Authorize.
+func Authorize(v *JWTVerifier, check func(*TokenClaims) error) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get("Authorization")
+			if token == "" {
+				http.Error(w, "Authorization token missing", http.StatusUnauthorized)
+				return
+			}
+			claims, err := v.Verify(r.Context(), token)
+			switch {
+			case errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrTokenExpired):
+				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				return
+			case err != nil:
+				http.Error(w, "Token verification unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			if check != nil {
+				if err := check(claims); err != nil {
+					http.Error(w, "Forbidden", http.StatusForbidden)
+					return
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsContextKey{}, claims)))
+		})
+	}
+}
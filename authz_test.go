@@ -0,0 +1,106 @@
+package supabase
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClaimsHelpers(t *testing.T) {
+	token := signJWT(t, "HS256", "", nil, map[string]any{
+		"sub":  "u1",
+		"role": "authenticated",
+		"app_metadata": map[string]any{
+			"tier":  "pro",
+			"roles": []string{"editor", "admin"},
+			"seats": 3,
+		},
+	})
+	claims, err := Claims(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RequireRole(claims, "authenticated", "service_role"); err != nil {
+		t.Errorf("Expected role to be accepted, got %v", err)
+	}
+	if err := RequireRole(claims, "service_role"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+	if err := RequireRole(nil, "service_role"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for nil claims, got %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		value any
+		want  bool
+	}{
+		{"app_metadata.tier", "pro", true},
+		{"app_metadata.tier", "free", false},
+		{"app_metadata.roles", "admin", true},
+		{"app_metadata.roles", []string{"editor", "admin"}, true},
+		{"app_metadata.seats", 3, true},
+		{"app_metadata.missing", "x", false},
+		{"sub.nested", "x", false},
+	}
+	for _, tt := range tests {
+		if got := HasClaim(claims, tt.path, tt.value); got != tt.want {
+			t.Errorf("HasClaim(%q, %v) = %v, want %v", tt.path, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Accept every token at /user so HS256 tokens verify remotely, except
+		// the one standing in for an auth server outage.
+		if claims, _ := Claims(r.Header.Get("Authorization")); claims != nil && claims.Role == "outage" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"id":"u1"}`))
+	}))
+	defer server.Close()
+	verifier := NewJWTVerifier(NewClient(server.URL, "key", "", WithRetryPolicy(RetryPolicy{MaxAttempts: 1})))
+
+	var got *TokenClaims
+	handler := Authorize(verifier, func(c *TokenClaims) error {
+		if HasClaim(c, "app_metadata.admin", true) {
+			return nil
+		}
+		return RequireRole(c, "admin")
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = ClaimsFromContext(r.Context())
+	}))
+
+	exp := time.Now().Add(time.Hour).Unix()
+	tests := []struct {
+		token string
+		want  int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer garbage", http.StatusUnauthorized},
+		{"Bearer " + signJWT(t, "HS256", "", nil, map[string]any{"role": "authenticated", "exp": exp - 2*3600}), http.StatusUnauthorized},
+		{"Bearer " + signJWT(t, "HS256", "", nil, map[string]any{"role": "authenticated", "exp": exp}), http.StatusForbidden},
+		{"Bearer " + signJWT(t, "HS256", "", nil, map[string]any{"role": "authenticated", "exp": exp, "app_metadata": map[string]any{"admin": true}}), http.StatusOK},
+		{"Bearer " + signJWT(t, "HS256", "", nil, map[string]any{"role": "outage", "exp": exp}), http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		got = nil
+		req := httptest.NewRequest("GET", "/admin", nil)
+		if tt.token != "" {
+			req.Header.Set("Authorization", tt.token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("Expected status %d, got %d", tt.want, rec.Code)
+		}
+		if (rec.Code == http.StatusOK) != (got != nil) {
+			t.Errorf("Expected claims in context only for allowed requests")
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Batch collects writes to run in a single call to a Postgres function, so
+// they commit or roll back together. PostgREST has no native batching; the
+// function runs the statements in its own transaction. Create one with
+// Client.Batch:
+//
+//	results, err := client.Batch("run_batch").
+//		Insert("orders", []Order{{ID: 1, Total: 20}}).
+//		Update("customers", map[string]any{"last_order": 1}, map[string]any{"id": 7}).
+//		Delete("carts", map[string]any{"customer_id": 7}).
+//		Execute(ctx)
+//
+// The function receives {"statements": [...]}, each statement an object with
+// op ("insert", "update", or "delete"), table, rows (insert), values
+// (update), and match (update and delete; column equality), and must return
+// one {"rows": [...], "count": n} object per statement. The README has a
+// plpgsql implementation. Statements run inside the function, out of reach
+// of WithTenant scoping, so Execute fails on a tenant-scoped client.
+type Batch struct {
+	client     *Client
+	function   string
+	statements []batchStatement
+}
+
+type batchStatement struct {
+	Op     string         `json:"op"`
+	Table  string         `json:"table"`
+	Rows   any            `json:"rows,omitempty"`
+	Values any            `json:"values,omitempty"`
+	Match  map[string]any `json:"match,omitempty"`
+}
+
+// BatchResult is the outcome of one statement of a Batch.
+type BatchResult struct {
+	// Rows holds the rows the statement wrote, as a JSON array.
+	Rows  json.RawMessage `json:"rows"`
+	Count int             `json:"count"`
+
+	codec Codec
+}
+
+// Decode decodes the rows of the result into dst with the codec of the
+// client that ran the batch.
+func (r BatchResult) Decode(dst any) error {
+	codec := r.codec
+	if codec == nil {
+		codec = jsonCodec{}
+	}
+	if err := codec.Unmarshal(r.Rows, dst); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	return nil
+}
+
+// Batch starts a batch run by the Postgres function named function.
+func (c *Client) Batch(function string) *Batch {
+	return &Batch{client: c, function: function}
+}
+
+// Insert adds an insert of rows, a struct, map, or slice of them, into table.
+func (b *Batch) Insert(table string, rows any) *Batch {
+	b.statements = append(b.statements, batchStatement{Op: "insert", Table: table, Rows: rows})
+	return b
+}
+
+// Update adds an update setting values on the rows of table whose columns
+// equal match.
+func (b *Batch) Update(table string, values any, match map[string]any) *Batch {
+	b.statements = append(b.statements, batchStatement{Op: "update", Table: table, Values: values, Match: match})
+	return b
+}
+
+// Delete adds a delete of the rows of table whose columns equal match.
+func (b *Batch) Delete(table string, match map[string]any) *Batch {
+	b.statements = append(b.statements, batchStatement{Op: "delete", Table: table, Match: match})
+	return b
+}
+
+// Execute calls the function with all statements and returns one result per
+// statement, in order. Updates and deletes without a match are refused
+// before anything is sent. If any statement fails, the function's
+// transaction is rolled back and the error is returned.
+func (b *Batch) Execute(ctx context.Context) ([]BatchResult, error) {
+	if b.client.tenantColumn != "" {
+		return nil, fmt.Errorf("batch function %s cannot be tenant scoped; run it on a client without WithTenant", b.function)
+	}
+	for i, s := range b.statements {
+		if s.Op != "insert" && len(s.Match) == 0 {
+			return nil, fmt.Errorf("batch statement %d: %s on %s requires a match", i, s.Op, s.Table)
+		}
+	}
+	body, err := b.client.getCodec().Marshal(map[string]any{"statements": b.statements})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %v", err)
+	}
+	resp, err := b.client.send(ctx, http.MethodPost, "rpc/"+b.function, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	var results []BatchResult
+	if err := b.client.getCodec().Unmarshal(resp.Body, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	for i := range results {
+		results[i].codec = b.client.getCodec()
+	}
+	if len(results) != len(b.statements) {
+		return results, fmt.Errorf("batch function %s returned %d results for %d statements", b.function, len(results), len(b.statements))
+	}
+	return results, nil
+}
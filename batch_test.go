@@ -0,0 +1,81 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	var got map[string][]map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/v1/rpc/run_batch" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+		w.Write([]byte(`[{"rows":[{"id":1,"total":20}],"count":1},{"rows":[],"count":0},{"rows":[{"id":3}],"count":1}]`))
+	}))
+	defer server.Close()
+
+	results, err := NewClient(server.URL, "key", "token").Batch("run_batch").
+		Insert("orders", []map[string]any{{"id": 1, "total": 20}}).
+		Update("customers", map[string]any{"last_order": 1}, map[string]any{"id": 7}).
+		Delete("carts", map[string]any{"customer_id": 7}).
+		Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	statements := got["statements"]
+	if len(statements) != 3 || statements[0]["op"] != "insert" || statements[1]["table"] != "customers" || statements[2]["match"] == nil {
+		t.Errorf("Unexpected payload %v", got)
+	}
+	if _, ok := statements[0]["match"]; ok {
+		t.Errorf("Expected insert without match, got %v", statements[0])
+	}
+
+	var orders []struct {
+		ID    int `json:"id"`
+		Total int `json:"total"`
+	}
+	if err := results[0].Decode(&orders); err != nil || len(orders) != 1 || orders[0].Total != 20 {
+		t.Errorf("Expected decoded order, got %+v (%v)", orders, err)
+	}
+	if results[1].Count != 0 || results[2].Count != 1 {
+		t.Errorf("Unexpected counts %+v", results)
+	}
+}
+
+func TestBatchRequiresMatch(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "key", "token", WithDryRun())
+	_, err := client.Batch("run_batch").Delete("carts", nil).Execute(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "requires a match") {
+		t.Errorf("Expected missing match error, got %v", err)
+	}
+}
+
+func TestBatchCodec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"rows":[{"id":1}],"count":1}]`))
+	}))
+	defer server.Close()
+
+	codec := &countingCodec{}
+	client := NewClient(server.URL, "your_api_key", "your_token", WithCodec(codec))
+	results, err := client.Batch("run_batch").Insert("Food", map[string]any{"id": 1}).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	var rows []map[string]any
+	if err := results[0].Decode(&rows); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if codec.marshals != 1 || codec.unmarshals != 2 {
+		t.Errorf("Expected the batch to use the client codec, got %d marshals and %d unmarshals", codec.marshals, codec.unmarshals)
+	}
+}
@@ -0,0 +1,353 @@
+package supabase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// QueryBuilder builds a PostgREST query against a table or view. Create one
+// with Client.From; methods return the builder so calls can be chained:
+//
+//	resp, err := client.From("Food").
+//		Select("id", "food_name").
+//		Eq("rating", 5).
+//		Order("id", false).
+//		Limit(10).
+//		Execute(ctx)
+type QueryBuilder struct {
+	client *Client
+	table  string
+	query  url.Values
+	// err is the first invalid identifier passed to the builder. It is
+	// returned when the query is built or run.
+	err error
+}
+
+// Column names a column in builder methods. Declaring a table's columns as
+// constants turns a renamed column into a compile error at every use instead
+// of a query that silently matches nothing:
+//
+//	const (
+//		FoodID     supabase.Column = "id"
+//		FoodName   supabase.Column = "food_name"
+//		FoodRating supabase.Column = "rating"
+//	)
+//
+//	client.From("Food").Select(FoodID, FoodName).Eq(FoodRating, 5)
+//
+// Untyped string constants are accepted too, so literal names still work.
+type Column string
+
+func (c Column) String() string {
+	return string(c)
+}
+
+// From starts a query against table. The name is escaped in the URL, so
+// names with spaces or capitals are passed as they are in the database; a
+// surrounding pair of double quotes is removed.
+func (c *Client) From(table string) *QueryBuilder {
+	q := &QueryBuilder{client: c, table: unquoteTable(table), query: url.Values{}}
+	if !c.skipQueryValidation {
+		q.err = validateTable(q.table)
+	}
+	return q
+}
+
+// checkColumns records the first invalid column for BuildURL and Execute to
+// return.
+func (q *QueryBuilder) checkColumns(columns ...Column) {
+	if q.err != nil || q.client.skipQueryValidation {
+		return
+	}
+	for _, column := range columns {
+		if err := validateColumn(column); err != nil {
+			q.err = err
+			return
+		}
+	}
+}
+
+// Select sets the columns to return. Without it all columns are returned.
+func (q *QueryBuilder) Select(columns ...Column) *QueryBuilder {
+	q.checkColumns(columns...)
+	q.query.Set("select", joinColumns(columns))
+	return q
+}
+
+// Eq filters rows where column equals value.
+func (q *QueryBuilder) Eq(column Column, value any) *QueryBuilder {
+	q.checkColumns(column)
+	q.query.Add(string(column), "eq."+formatValue(value))
+	return q
+}
+
+// Order sorts the result by column. Calls accumulate, so the first call sets
+// the primary sort key. column may be a JSON path (JSONText("data",
+// "priority")) or a column of an embedded to-one resource
+// (EmbeddedColumn("author", "name")).
+func (q *QueryBuilder) Order(column Column, ascending bool) *QueryBuilder {
+	q.addOrder("order", column, ascending)
+	return q
+}
+
+// OrderEmbedded sorts the rows of the embedded resource relation by column,
+// leaving the order of the parent rows unchanged:
+//
+//	client.From("authors").Select("name", "books(title)").OrderEmbedded("books", "title", true)
+//	// books.order=title.asc
+func (q *QueryBuilder) OrderEmbedded(relation string, column Column, ascending bool) *QueryBuilder {
+	q.addOrder(relation+".order", column, ascending)
+	return q
+}
+
+func (q *QueryBuilder) addOrder(key string, column Column, ascending bool) {
+	q.checkColumns(column)
+	direction := ".desc"
+	if ascending {
+		direction = ".asc"
+	}
+	if existing := q.query.Get(key); existing != "" {
+		q.query.Set(key, existing+","+string(column)+direction)
+	} else {
+		q.query.Set(key, string(column)+direction)
+	}
+}
+
+// EmbeddedColumn refers to column of the embedded to-one resource relation,
+// e.g. author(name). It is used to order parent rows by a related row:
+//
+//	client.From("books").Select("title", "author(name)").Order(supabase.EmbeddedColumn("author", "name"), true)
+//	// order=author(name).asc
+func EmbeddedColumn(relation string, column Column) Column {
+	return Column(relation + "(" + string(column) + ")")
+}
+
+// Embed selects columns of the related resource relation, nested under its
+// name in each parent row, so one request returns parents with their related
+// rows. Without columns every column is selected:
+//
+//	client.From("Food").Select("*", supabase.Embed("reviews", "rating", "body"))
+//	// select=*,reviews(rating,body)
+//	// [{"id": 1, ..., "reviews": [{"rating": 5, "body": "..."}]}]
+//
+// Rename the embedded field with As, e.g. Embed("users").As("author").
+func Embed(relation string, columns ...Column) Column {
+	if len(columns) == 0 {
+		columns = []Column{"*"}
+	}
+	return Column(relation + "(" + joinColumns(columns) + ")")
+}
+
+// Relation describes an embedded resource with the options PostgREST
+// accepts in a select, for embeds Embed and InnerJoin cannot express. When
+// two foreign keys point at the same table, Hint names the one to follow:
+//
+//	client.From("posts").Select("*",
+//		supabase.Relation{Name: "users", Alias: "author", Hint: "posts_author_id_fkey"}.Column(),
+//		supabase.Relation{Name: "users", Alias: "editor", Hint: "posts_editor_id_fkey", Columns: []supabase.Column{"name"}}.Column())
+//	// select=*,author:users!posts_author_id_fkey(*),editor:users!posts_editor_id_fkey(name)
+type Relation struct {
+	// Name is the related table or view, or a foreign key column.
+	Name string
+	// Alias renames the embedded field. It does not apply to spreads.
+	Alias string
+	// Hint is the foreign key constraint or column to join on.
+	Hint string
+	// Inner drops parent rows without a related row, as InnerJoin does.
+	Inner bool
+	// Spread flattens the columns of a to-one relation into the parent row,
+	// as Spread does.
+	Spread bool
+	// Columns defaults to every column.
+	Columns []Column
+}
+
+// Column returns the relation as a select item.
+func (r Relation) Column() Column {
+	name := r.Name
+	if r.Hint != "" {
+		name += "!" + r.Hint
+	}
+	if r.Inner {
+		name += "!inner"
+	}
+	column := Embed(name, r.Columns...)
+	if r.Spread {
+		return "..." + column
+	}
+	if r.Alias != "" {
+		column = column.As(r.Alias)
+	}
+	return column
+}
+
+// Spread selects columns of the to-one resource relation as if they were
+// columns of the parent, so joined values decode into a flat struct:
+//
+//	client.From("books").Select("title", supabase.Spread("author", "name", supabase.Column("country").As("author_country")))
+//	// select=title,...author(name,author_country:country)
+//	// [{"title": "...", "name": "...", "author_country": "..."}]
+//
+// Without columns every column is spread. To spread a relation that needs a
+// foreign key hint, set Spread on a Relation.
+func Spread(relation string, columns ...Column) Column {
+	return "..." + Embed(relation, columns...)
+}
+
+// InnerJoin embeds columns of relation with the !inner modifier, so filters
+// on the embedded resource (see ForEmbedded) also remove parent rows with no
+// matching related row. Without columns every column is selected:
+//
+//	client.From("customers").
+//		Select("name", supabase.InnerJoin("orders", "total")).
+//		Where(supabase.ForEmbedded("orders", supabase.Gte("total", 100)))
+//	// select=name,orders!inner(total)&orders.total=gte.100
+func InnerJoin(relation string, columns ...Column) Column {
+	return Embed(relation+"!inner", columns...)
+}
+
+// Limit caps the number of rows returned.
+func (q *QueryBuilder) Limit(n int) *QueryBuilder {
+	q.query.Set("limit", strconv.Itoa(n))
+	return q
+}
+
+// Offset skips the first n rows.
+func (q *QueryBuilder) Offset(n int) *QueryBuilder {
+	q.query.Set("offset", strconv.Itoa(n))
+	return q
+}
+
+// CountMethod selects how PostgREST counts the rows matching a query.
+type CountMethod string
+
+const (
+	// CountExact runs a full count, which can be slow on large tables.
+	CountExact CountMethod = "exact"
+	// CountPlanned uses the planner's estimate.
+	CountPlanned CountMethod = "planned"
+	// CountEstimated counts exactly up to the max-rows setting and uses the
+	// planner's estimate beyond it.
+	CountEstimated CountMethod = "estimated"
+)
+
+// Count asks PostgREST to report the total number of matching rows, which
+// Page and Response.PageInfo then return in PageInfo.Total.
+func (q *QueryBuilder) Count(method CountMethod) *QueryBuilder {
+	q.client = q.client.withPrefer("count=" + string(method))
+	return q
+}
+
+// Binary runs the query selecting only column and returns its raw value,
+// requested as application/octet-stream, so bytea columns such as thumbnails
+// arrive as bytes rather than hex or base64 inside JSON. Filter the query to a
+// single row; PostgREST concatenates the values of all matching rows.
+//
+//	thumb, err := client.From("images").Eq("id", 7).Binary(ctx, "thumbnail")
+func (q *QueryBuilder) Binary(ctx context.Context, column Column) ([]byte, error) {
+	q.checkColumns(column)
+	if q.err != nil {
+		return nil, q.err
+	}
+	query := cloneValues(q.query)
+	query.Set("select", string(column))
+	resp, err := q.client.WithAccept("application/octet-stream").send(ctx, http.MethodGet, q.table, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Page runs the query, decodes the rows into dst, and returns where they lie
+// in the full result. Without Count, Total is -1 and HasMore is inferred from
+// whether a full page (Limit rows) was returned.
+func (q *QueryBuilder) Page(ctx context.Context, dst any) (PageInfo, error) {
+	resp, err := q.Execute(ctx)
+	if err != nil {
+		return PageInfo{}, err
+	}
+	if err := q.client.getCodec().Unmarshal(resp.Body, dst); err != nil {
+		return PageInfo{}, fmt.Errorf("failed to decode response: %v", err)
+	}
+	info, ok := resp.PageInfo()
+	if !ok {
+		return PageInfo{}, fmt.Errorf("failed to parse Content-Range %q", resp.Header.Get("Content-Range"))
+	}
+	return info, nil
+}
+
+// Query returns a copy of the query parameters built so far.
+func (q *QueryBuilder) Query() url.Values {
+	return cloneValues(q.query)
+}
+
+// BuildURL returns the URL the query would be sent to.
+func (q *QueryBuilder) BuildURL() (*url.URL, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	return q.client.requestURL(q.table, q.query)
+}
+
+// String returns the URL the query would be sent to, or an error description
+// if it cannot be built.
+func (q *QueryBuilder) String() string {
+	u, err := q.BuildURL()
+	if err != nil {
+		return fmt.Sprintf("%%!(%v)", err)
+	}
+	return u.String()
+}
+
+// Prepare returns the request Execute would send, including headers, without
+// sending it.
+func (q *QueryBuilder) Prepare() (*PreparedRequest, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	return q.client.Prepare(http.MethodGet, q.table, q.query, nil)
+}
+
+// Execute runs the query and returns the response.
+func (q *QueryBuilder) Execute(ctx context.Context) (*Response, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	return q.client.send(ctx, http.MethodGet, q.table, cloneValues(q.query), nil)
+}
+
+// Decode runs the query and decodes the result into dst with the client's codec.
+func (q *QueryBuilder) Decode(ctx context.Context, dst any) error {
+	resp, err := q.Execute(ctx)
+	if err != nil {
+		return err
+	}
+	if err := q.client.getCodec().Unmarshal(resp.Body, dst); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	return nil
+}
+
+// formatValue renders a filter operand.
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return "null"
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+// cloneValues returns a deep copy of v.
+func cloneValues(v url.Values) url.Values {
+	return url.Values(http.Header(v).Clone())
+}
@@ -0,0 +1,202 @@
+package supabase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Write([]byte(`[{"id":3,"food_name":"Soba"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token")
+	q := client.From("Food").
+		Select("id", "food_name").
+		Eq("rating", 5).
+		Order("rating", false).
+		Order("id", true).
+		Limit(10).
+		Offset(20)
+
+	want := server.URL + "/rest/v1/Food?limit=10&offset=20&order=rating.desc%2Cid.asc&rating=eq.5&select=id%2Cfood_name"
+	if got := q.String(); got != want {
+		t.Errorf("Expected URL %s, got %s", want, got)
+	}
+
+	var rows []struct {
+		ID       int    `json:"id"`
+		FoodName string `json:"food_name"`
+	}
+	if err := q.Decode(context.Background(), &rows); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].FoodName != "Soba" {
+		t.Errorf("Expected decoded row, got %+v", rows)
+	}
+	if server.URL+"/rest/v1/Food?"+gotQuery != want {
+		t.Errorf("Expected request query %s, got %s", want, gotQuery)
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{"text", "text"},
+		{42, "42"},
+		{true, "true"},
+		{nil, "null"},
+		{time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), "2024-05-01T12:00:00Z"},
+	}
+	for _, tt := range tests {
+		if got := formatValue(tt.value); got != tt.want {
+			t.Errorf("formatValue(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestQueryBuilderColumns(t *testing.T) {
+	const (
+		foodID     Column = "id"
+		foodRating Column = "rating"
+	)
+	q := NewClient("https://example.supabase.co", "k", "t").From("Food").Select(foodID, "food_name").Eq(foodRating, 5).Order(foodID, true)
+	want := "https://example.supabase.co/rest/v1/Food?order=id.asc&rating=eq.5&select=id%2Cfood_name"
+	if got := q.String(); got != want {
+		t.Errorf("Expected URL %s, got %s", want, got)
+	}
+}
+
+func TestOrderJSONAndEmbedded(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "key", "token")
+	q := client.From("books").
+		Select("title", "author(name)", "reviews(stars)").
+		Order(JSONText("data", "priority"), false).
+		Order(EmbeddedColumn("author", "name"), true).
+		OrderEmbedded("reviews", "stars", false)
+
+	query := q.Query()
+	if got, want := query.Get("order"), "data->>priority.desc,author(name).asc"; got != want {
+		t.Errorf("Expected order %s, got %s", want, got)
+	}
+	if got, want := query.Get("reviews.order"), "stars.desc"; got != want {
+		t.Errorf("Expected reviews.order %s, got %s", want, got)
+	}
+	if _, err := q.Prepare(); err != nil {
+		t.Errorf("Expected query to pass validation, got %v", err)
+	}
+	if err := validateParam("order", `data->>"a.b".desc`); err != nil {
+		t.Errorf("Expected quoted JSON key to pass validation, got %v", err)
+	}
+}
+
+func TestQueryBuilderPage(t *testing.T) {
+	var prefer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prefer = r.Header.Get("Prefer")
+		if prefer == "" {
+			w.Header().Set("Content-Range", "0-1/*")
+		} else {
+			w.Header().Set("Content-Range", "0-1/5")
+		}
+		w.Write([]byte(`[{"id":1},{"id":2}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token")
+	var rows []map[string]any
+	info, err := client.From("Food").Limit(2).Count(CountExact).Page(context.Background(), &rows)
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	if prefer != "count=exact" {
+		t.Errorf("Expected Prefer count=exact, got %q", prefer)
+	}
+	if want := (PageInfo{From: 0, To: 1, Total: 5, HasMore: true}); info != want || len(rows) != 2 {
+		t.Errorf("Expected %+v with 2 rows, got %+v with %d", want, info, len(rows))
+	}
+
+	info, err = client.From("Food").Limit(2).Page(context.Background(), &rows)
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	if want := (PageInfo{From: 0, To: 1, Total: -1, HasMore: true}); info != want {
+		t.Errorf("Expected %+v, got %+v", want, info)
+	}
+}
+
+func TestQueryBuilderBinary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/octet-stream" || r.URL.Query().Get("select") != "thumbnail" {
+			t.Errorf("Unexpected request Accept %q select %q", r.Header.Get("Accept"), r.URL.Query().Get("select"))
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte{0x89, 'P', 'N', 'G'})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token")
+	data, err := client.From("images").Select("id").Eq("id", 7).Binary(context.Background(), "thumbnail")
+	if err != nil {
+		t.Fatalf("Binary returned error: %v", err)
+	}
+	if string(data) != "\x89PNG" {
+		t.Errorf("Expected raw bytes, got %q", data)
+	}
+}
+
+func TestSpread(t *testing.T) {
+	q := NewClient("https://example.supabase.co", "key", "token").From("books").
+		Select("title", Spread("author", "name", Column("country").As("author_country")))
+	if got, want := q.Query().Get("select"), "title,...author(name,author_country:country)"; got != want {
+		t.Errorf("Expected select %s, got %s", want, got)
+	}
+	if got := Spread("author"); got != "...author(*)" {
+		t.Errorf("Expected spread of every column, got %s", got)
+	}
+	if _, err := q.Prepare(); err != nil {
+		t.Errorf("Expected spread to pass validation, got %v", err)
+	}
+}
+
+func TestEmbed(t *testing.T) {
+	q := NewClient("https://example.supabase.co", "key", "token").From("posts").
+		Select("*", Embed("reviews"), Embed("users", "id", "name").As("author"))
+	if got, want := q.Query().Get("select"), "*,reviews(*),author:users(id,name)"; got != want {
+		t.Errorf("Expected select %s, got %s", want, got)
+	}
+	if _, err := q.Prepare(); err != nil {
+		t.Errorf("Expected embed to pass validation, got %v", err)
+	}
+}
+
+func TestRelation(t *testing.T) {
+	tests := []struct {
+		relation Relation
+		want     Column
+	}{
+		{Relation{Name: "reviews"}, "reviews(*)"},
+		{Relation{Name: "users", Alias: "author", Hint: "posts_author_id_fkey"}, "author:users!posts_author_id_fkey(*)"},
+		{Relation{Name: "users", Hint: "editor_id", Inner: true, Columns: []Column{"id", "name"}}, "users!editor_id!inner(id,name)"},
+		{Relation{Name: "users", Alias: "author", Hint: "posts_author_id_fkey", Spread: true, Columns: []Column{"name"}}, "...users!posts_author_id_fkey(name)"},
+	}
+	for _, tt := range tests {
+		if got := tt.relation.Column(); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+	}
+
+	q := NewClient("https://example.supabase.co", "key", "token").From("posts").
+		Select("*", Relation{Name: "users", Alias: "author", Hint: "posts_author_id_fkey"}.Column())
+	if _, err := q.Prepare(); err != nil {
+		t.Errorf("Expected relation to pass validation, got %v", err)
+	}
+}
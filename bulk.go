@@ -0,0 +1,141 @@
+package supabase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// BulkWriter inserts large slices of rows by splitting them into chunks that
+// are written concurrently, each with its own retries. Adjust the exported
+// fields before calling Write.
+type BulkWriter[T any] struct {
+	client *Client
+	table  string
+
+	// ChunkSize is the number of rows sent per request.
+	ChunkSize int
+	// Concurrency is the maximum number of chunks in flight.
+	Concurrency int
+	// Retry is applied to each chunk independently, in place of the
+	// client's retry policy.
+	Retry RetryPolicy
+	// Columns, when set, limits the columns written; other fields of the
+	// encoded rows are ignored. See Client.Insert.
+	Columns []Column
+}
+
+// NewBulkWriter creates a BulkWriter for table with 500-row chunks, four
+// concurrent requests, and DefaultRetryPolicy.
+func NewBulkWriter[T any](client *Client, table string) *BulkWriter[T] {
+	return &BulkWriter[T]{
+		client:      client,
+		table:       table,
+		ChunkSize:   500,
+		Concurrency: 4,
+		Retry:       DefaultRetryPolicy,
+	}
+}
+
+// ChunkError reports the failure of a single chunk. Rows [Start, End) of the
+// input were not written.
+type ChunkError struct {
+	Index int
+	Start int
+	End   int
+	Err   error
+}
+
+func (e ChunkError) Error() string {
+	return fmt.Sprintf("chunk %d (rows %d-%d): %v", e.Index, e.Start, e.End-1, e.Err)
+}
+
+func (e ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// BulkError is returned by BulkWriter.Write when one or more chunks fail.
+// Chunks not listed were written successfully.
+type BulkError struct {
+	Chunks []ChunkError
+	Total  int
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("supabase: %d of %d chunks failed; first: %v", len(e.Chunks), e.Total, e.Chunks[0])
+}
+
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, len(e.Chunks))
+	for i, chunk := range e.Chunks {
+		errs[i] = chunk
+	}
+	return errs
+}
+
+// Write inserts rows in chunks. It waits for all chunks to finish and returns
+// a *BulkError listing the chunks that failed. Chunks not yet started when ctx
+// is cancelled are reported as failed with the context error.
+func (w *BulkWriter[T]) Write(ctx context.Context, rows []T) error {
+	chunkSize := max(w.ChunkSize, 1)
+	concurrency := max(w.Concurrency, 1)
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []ChunkError
+		total  int
+	)
+	sem := make(chan struct{}, concurrency)
+	for start := 0; start < len(rows); start += chunkSize {
+		chunk := ChunkError{Index: total, Start: start, End: min(start+chunkSize, len(rows))}
+		total++
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			chunk.Err = ctx.Err()
+			mu.Lock()
+			failed = append(failed, chunk)
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := w.writeChunk(ctx, rows[chunk.Start:chunk.End]); err != nil {
+				chunk.Err = err
+				mu.Lock()
+				failed = append(failed, chunk)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failed) == 0 {
+		return nil
+	}
+	slices.SortFunc(failed, func(a, b ChunkError) int { return a.Index - b.Index })
+	return &BulkError{Chunks: failed, Total: total}
+}
+
+// writeChunk encodes and inserts one chunk like Client.Insert, retrying
+// transient failures under w.Retry. The chunk carries its own idempotency
+// key, the same on every attempt, so a retry after a timeout cannot insert
+// its rows twice.
+func (w *BulkWriter[T]) writeChunk(ctx context.Context, rows []T) error {
+	data, err := w.client.getCodec().Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %v", err)
+	}
+	client := w.client.WithIdempotencyKey(NewIdempotencyKey())
+	retry := w.Retry
+	client.retryPolicy = &retry
+	client.methodRetryPolicies = nil
+	_, err = client.Insert(ctx, w.table, data, w.Columns...)
+	return err
+}
@@ -0,0 +1,103 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type bulkRow struct {
+	ID int `json:"id"`
+}
+
+func TestBulkWriter(t *testing.T) {
+	var mu sync.Mutex
+	attempts := map[int]int{}
+	keys := map[int][]string{}
+	written := map[int]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var rows []bulkRow
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &rows); err != nil {
+			t.Errorf("Invalid chunk body %s", body)
+		}
+		first := rows[0].ID
+
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[first]++
+		keys[first] = append(keys[first], r.Header.Get("Idempotency-Key"))
+		switch {
+		case first == 2 && attempts[first] == 1:
+			// Transient failure; the chunk is retried.
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		case first == 4:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"bad row"}`))
+			return
+		}
+		for _, row := range rows {
+			written[row.ID] = true
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	rows := make([]bulkRow, 5)
+	for i := range rows {
+		rows[i].ID = i
+	}
+
+	writer := NewBulkWriter[bulkRow](NewClient(server.URL, "your_api_key", "your_token"), "Food")
+	writer.ChunkSize = 2
+	writer.Concurrency = 2
+	writer.Retry = RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}
+
+	err := writer.Write(context.Background(), rows)
+	var bulkErr *BulkError
+	if !errors.As(err, &bulkErr) {
+		t.Fatalf("Expected *BulkError, got %v", err)
+	}
+	if bulkErr.Total != 3 || len(bulkErr.Chunks) != 1 {
+		t.Fatalf("Expected 1 of 3 chunks to fail, got %+v", bulkErr)
+	}
+	chunk := bulkErr.Chunks[0]
+	if chunk.Index != 2 || chunk.Start != 4 || chunk.End != 5 {
+		t.Errorf("Unexpected failed chunk %+v", chunk)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected chunk error to wrap the API error, got %v", chunk.Err)
+	}
+	if attempts[4] != 1 {
+		t.Errorf("Expected non-retryable chunk to be attempted once, got %d", attempts[4])
+	}
+	// Each chunk has its own key, reused when the chunk is retried.
+	if len(keys[2]) != 2 || keys[2][0] == "" || keys[2][0] != keys[2][1] || keys[0][0] == keys[2][0] {
+		t.Errorf("Expected a stable idempotency key per chunk, got %v", keys)
+	}
+	for id := 0; id < 4; id++ {
+		if !written[id] {
+			t.Errorf("Expected row %d to be written", id)
+		}
+	}
+}
+
+func TestBulkWriterCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	writer := NewBulkWriter[bulkRow](NewClient("http://127.0.0.1:0", "your_api_key", "your_token"), "Food")
+	writer.Concurrency = 1
+	err := writer.Write(ctx, make([]bulkRow, 10))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jtclarkjr/supabase-go-rest"
+)
+
+// runExport dumps a table to a file, resuming an interrupted export of the
+// same file.
+func runExport(args []string, stdout io.Writer, getenv func(string) string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	var (
+		conn    connFlags
+		eqs     multiFlag
+		filters multiFlag
+	)
+	conn.register(fs, getenv)
+	fs.Var(&eqs, "eq", "equality filter column=value (repeatable)")
+	fs.Var(&filters, "filter", "PostgREST filter column=op.value, e.g. rating=gte.4 (repeatable)")
+	selectCols := fs.String("select", "", "columns to export, e.g. id,food_name; must include the key")
+	out := fs.String("out", "", "file to write; an interrupted export of the same file resumes")
+	format := fs.String("format", "jsonl", "output format: jsonl or csv")
+	compress := fs.Bool("gzip", false, "gzip the output")
+	by := fs.String("by", "id", "unique column to page by; must be selected")
+	pageSize := fs.Int("page-size", 1000, "rows per request")
+
+	table, err := parseCommand(fs, args)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		return fmt.Errorf("missing --out file")
+	}
+
+	client, err := conn.client(getenv)
+	if err != nil {
+		return err
+	}
+	q := client.From(table)
+	for _, eq := range eqs {
+		column, value, ok := strings.Cut(eq, "=")
+		if !ok {
+			return fmt.Errorf("invalid --eq %q, want column=value", eq)
+		}
+		q.Eq(supabase.Column(column), value)
+	}
+	for _, filter := range filters {
+		column, value, ok := strings.Cut(filter, "=")
+		operator, operand, hasOperand := strings.Cut(value, ".")
+		if !ok || !hasOperand {
+			return fmt.Errorf("invalid --filter %q, want column=op.value", filter)
+		}
+		q.Where(supabase.Filter{Column: supabase.Column(column), Operator: operator, Value: operand})
+	}
+	if *selectCols != "" {
+		q.Select(supabase.Column(*selectCols))
+	}
+
+	n, err := supabase.ExportFile(context.Background(), q, *out, supabase.ExportOptions{
+		Format:   supabase.ExportFormat(*format),
+		Key:      supabase.Column(*by),
+		PageSize: *pageSize,
+		Gzip:     *compress,
+	})
+	if err != nil {
+		return fmt.Errorf("export stopped after %d rows, run again to resume: %v", n, err)
+	}
+	fmt.Fprintf(stdout, "exported %d rows to %s\n", n, *out)
+	return nil
+}
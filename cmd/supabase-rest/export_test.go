@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jtclarkjr/supabase-go-rest/supabasetest"
+)
+
+func TestExport(t *testing.T) {
+	server := supabasetest.NewServer()
+	defer server.Close()
+	server.Seed("Food",
+		supabasetest.Row{"id": 1, "food_name": "Ramen", "rating": 5},
+		supabasetest.Row{"id": 2, "food_name": "Udon", "rating": 3},
+		supabasetest.Row{"id": 3, "food_name": "Soba", "rating": 5},
+	)
+	path := filepath.Join(t.TempDir(), "food.csv")
+
+	var out bytes.Buffer
+	args := []string{"export", "Food", "--filter", "rating=gte.4", "--select", "id,food_name", "--format", "csv", "--page-size", "1", "--out", path}
+	if err := run(args, &out, testEnv(server.URL)); err != nil {
+		t.Fatalf("run(%v) returned error: %v", args, err)
+	}
+	if want := "exported 2 rows to " + path + "\n"; out.String() != want {
+		t.Errorf("run(%v) printed %q, want %q", args, out.String(), want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "food_name,id\nRamen,1\nSoba,3\n"; string(data) != want {
+		t.Errorf("exported file:\n%s\nwant:\n%s", data, want)
+	}
+
+	if err := run([]string{"export", "Food"}, &out, testEnv(server.URL)); err == nil {
+		t.Error("export without --out returned no error")
+	}
+}
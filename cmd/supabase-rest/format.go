@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// writeRows renders a JSON array of rows in the requested format. columns
+// fixes the column order for table and csv output; when empty, the union of
+// all keys is used in alphabetical order.
+func writeRows(w io.Writer, format string, body []byte, columns []string) error {
+	switch format {
+	case "json":
+		var out bytes.Buffer
+		if err := json.Indent(&out, bytes.TrimSpace(body), "", "  "); err != nil {
+			return fmt.Errorf("invalid JSON response: %v", err)
+		}
+		out.WriteByte('\n')
+		_, err := out.WriteTo(w)
+		return err
+	case "table", "csv":
+	default:
+		return fmt.Errorf("unknown format %q, want json, table, or csv", format)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var rows []map[string]any
+	if err := decoder.Decode(&rows); err != nil {
+		return fmt.Errorf("response is not a list of rows: %v", err)
+	}
+	if len(columns) == 0 {
+		columns = rowKeys(rows)
+	}
+
+	if format == "csv" {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			record := make([]string, len(columns))
+			for i, column := range columns {
+				record[i] = cell(row[column], "")
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+	for _, row := range rows {
+		values := make([]string, len(columns))
+		for i, column := range columns {
+			values[i] = cell(row[column], "NULL")
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+// rowKeys returns the sorted union of keys across rows.
+func rowKeys(rows []map[string]any) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, row := range rows {
+		for key := range row {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// selectColumns derives output column names from a select parameter,
+// resolving aliases ("name:food_name" yields "name").
+func selectColumns(selectParam string) []string {
+	if selectParam == "" || strings.ContainsAny(selectParam, "*(") {
+		return nil
+	}
+	var columns []string
+	for _, item := range strings.Split(selectParam, ",") {
+		alias, _, _ := strings.Cut(strings.TrimSpace(item), ":")
+		columns = append(columns, alias)
+	}
+	return columns
+}
+
+// cell renders a JSON value for table and csv output.
+func cell(value any, null string) string {
+	switch v := value.(type) {
+	case nil:
+		return null
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
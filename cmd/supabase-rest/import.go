@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jtclarkjr/supabase-go-rest"
+)
+
+// runImport loads a JSON, JSON Lines, or CSV file into a table.
+func runImport(args []string, stdout io.Writer, getenv func(string) string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	var (
+		conn     connFlags
+		mappings multiFlag
+	)
+	conn.register(fs, getenv)
+	fs.Var(&mappings, "map", "rename a field to a column, field=column, or drop it with field= (repeatable)")
+	in := fs.String("in", "", "file to read; .csv files are read as CSV and .gz files are decompressed")
+	format := fs.String("format", "", "input format: json or csv (default from the file name)")
+	onConflict := fs.String("on-conflict", "", "columns of the unique constraint to upsert on (default primary key)")
+	ignoreDuplicates := fs.Bool("ignore-duplicates", false, "skip rows that already exist instead of updating them")
+	chunkSize := fs.Int("chunk-size", 500, "rows per request")
+	dryRun := fs.Bool("dry-run", false, "read and check the file without writing")
+
+	table, err := parseCommand(fs, args)
+	if err != nil {
+		return err
+	}
+	if *in == "" {
+		return fmt.Errorf("missing --in file")
+	}
+	opts := supabase.ImportOptions{
+		Format:           supabase.ImportFormat(*format),
+		ChunkSize:        *chunkSize,
+		Columns:          map[string]supabase.Column{},
+		IgnoreDuplicates: *ignoreDuplicates,
+		DryRun:           *dryRun,
+	}
+	for _, mapping := range mappings {
+		field, column, ok := strings.Cut(mapping, "=")
+		if !ok {
+			return fmt.Errorf("invalid --map %q, want field=column", mapping)
+		}
+		opts.Columns[field] = supabase.Column(column)
+	}
+	if *onConflict != "" {
+		for _, column := range strings.Split(*onConflict, ",") {
+			opts.OnConflict = append(opts.OnConflict, supabase.Column(column))
+		}
+	}
+
+	client, err := conn.client(getenv)
+	if err != nil {
+		return err
+	}
+	result, err := client.ImportFile(context.Background(), table, *in, opts)
+	if err != nil {
+		return fmt.Errorf("import stopped after %d rows: %v", result.Rows, err)
+	}
+	if *dryRun {
+		fmt.Fprintf(stdout, "dry run: would import %d rows in %d chunks\n", result.Rows, result.Chunks)
+		return nil
+	}
+	fmt.Fprintf(stdout, "imported %d rows in %d chunks\n", result.Rows, result.Chunks)
+	return nil
+}
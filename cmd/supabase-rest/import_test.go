@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jtclarkjr/supabase-go-rest/supabasetest"
+)
+
+func TestImport(t *testing.T) {
+	server := supabasetest.NewServer()
+	defer server.Close()
+	server.Seed("Food")
+	path := filepath.Join(t.TempDir(), "food.csv")
+	if err := os.WriteFile(path, []byte("id,name,notes\n1,Ramen,x\n2,Udon,y\n3,Soba,z\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	args := []string{"import", "Food", "--in", path, "--map", "name=food_name", "--map", "notes=", "--chunk-size", "2", "--dry-run"}
+	if err := run(args, &out, testEnv(server.URL)); err != nil {
+		t.Fatalf("run(%v) returned error: %v", args, err)
+	}
+	if want := "dry run: would import 3 rows in 2 chunks\n"; out.String() != want {
+		t.Errorf("run(%v) printed %q, want %q", args, out.String(), want)
+	}
+	if rows := server.Rows("Food"); len(rows) != 0 {
+		t.Errorf("dry run wrote rows: %v", rows)
+	}
+
+	out.Reset()
+	args = args[:len(args)-1]
+	if err := run(args, &out, testEnv(server.URL)); err != nil {
+		t.Fatalf("run(%v) returned error: %v", args, err)
+	}
+	if want := "imported 3 rows in 2 chunks\n"; out.String() != want {
+		t.Errorf("run(%v) printed %q, want %q", args, out.String(), want)
+	}
+	rows := server.Rows("Food")
+	if len(rows) != 3 || rows[2]["food_name"] != "Soba" || rows[2]["notes"] != nil {
+		t.Errorf("imported rows = %v", rows)
+	}
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jtclarkjr/supabase-go-rest"
+)
+
+// stdin is read for passwords and one-time codes.
+var stdin io.Reader = os.Stdin
+
+// configDirEnv overrides the directory the session file is stored in.
+const configDirEnv = "SUPABASE_REST_CONFIG_DIR"
+
+// passwordEnv holds the password for non-interactive password logins. There
+// is no flag for it, since flags show up in ps and shell history.
+const passwordEnv = "SUPABASE_PASSWORD"
+
+// sessionFile returns the path of the session file.
+func sessionFile(getenv func(string) string) (string, error) {
+	dir := getenv(configDirEnv)
+	if dir == "" {
+		base, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(base, "supabase-rest")
+	}
+	return filepath.Join(dir, "sessions.json"), nil
+}
+
+// loadSessions reads the stored sessions, keyed by project URL.
+func loadSessions(getenv func(string) string) (map[string]supabase.Session, error) {
+	path, err := sessionFile(getenv)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]supabase.Session{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sessions := map[string]supabase.Session{}
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %v", path, err)
+	}
+	return sessions, nil
+}
+
+// saveSession stores s for projectURL, or removes the entry when s is nil.
+// The file is only readable by the current user.
+func saveSession(getenv func(string) string, projectURL string, s *supabase.Session) error {
+	sessions, err := loadSessions(getenv)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		delete(sessions, projectURL)
+	} else {
+		sessions[projectURL] = *s
+	}
+	path, err := sessionFile(getenv)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// storedToken returns the access token of the stored session for projectURL,
+// refreshing and re-saving it when it has expired. It returns "" when no
+// session is stored.
+func storedToken(getenv func(string) string, projectURL, key string) (string, error) {
+	sessions, err := loadSessions(getenv)
+	if err != nil {
+		return "", err
+	}
+	s, ok := sessions[projectURL]
+	if !ok {
+		return "", nil
+	}
+	if !s.Expired(time.Minute) {
+		return s.AccessToken, nil
+	}
+	refreshed, err := signIn(authClient(projectURL, key), "token?grant_type=refresh_token", map[string]string{"refresh_token": s.RefreshToken})
+	if err != nil {
+		return "", fmt.Errorf("session expired and could not be refreshed (%v); run supabase-rest login", err)
+	}
+	if err := saveSession(getenv, projectURL, refreshed); err != nil {
+		return "", err
+	}
+	return refreshed.AccessToken, nil
+}
+
+func runLogin(args []string, stdout io.Writer, getenv func(string) string) error {
+	fs := flag.NewFlagSet("login", flag.ContinueOnError)
+	var conn connFlags
+	conn.register(fs, getenv)
+	email := fs.String("email", "", "email address to sign in with")
+	phone := fs.String("phone", "", "phone number to sign in with")
+	otp := fs.Bool("otp", false, "sign in with a one-time code instead of a password")
+	provider := fs.String("provider", "", "sign in with an OAuth provider in the browser (PKCE), e.g. github")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if conn.url == "" || conn.key == "" {
+		return fmt.Errorf("project URL and API key are required; set SUPABASE_URL and SUPABASE_ANON_KEY or pass --url and --key")
+	}
+
+	client := authClient(conn.url, conn.key)
+	var (
+		s   *supabase.Session
+		err error
+	)
+	switch {
+	case *provider != "":
+		s, err = loginPKCE(client, *provider, stdout)
+	case *email == "" && *phone == "":
+		return fmt.Errorf("one of --email, --phone, or --provider is required")
+	case *otp:
+		s, err = loginOTP(client, *email, *phone, stdout)
+	default:
+		password := getenv(passwordEnv)
+		if password == "" {
+			if password, err = readPassword(stdout, "Password: "); err != nil {
+				return err
+			}
+		}
+		s, err = signIn(client, "token?grant_type=password", map[string]string{
+			"email": *email, "phone": *phone, "password": password,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if err := saveSession(getenv, conn.url, s); err != nil {
+		return fmt.Errorf("failed to save session: %v", err)
+	}
+	var name string
+	if s.User != nil {
+		name = cmp.Or(s.User.Email, s.User.Phone)
+	}
+	fmt.Fprintf(stdout, "Logged in as %s\n", cmp.Or(name, "user"))
+	return nil
+}
+
+func runLogout(args []string, stdout io.Writer, getenv func(string) string) error {
+	fs := flag.NewFlagSet("logout", flag.ContinueOnError)
+	var conn connFlags
+	conn.register(fs, getenv)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := saveSession(getenv, conn.url, nil); err != nil {
+		return err
+	}
+	fmt.Fprintln(stdout, "Logged out")
+	return nil
+}
+
+// loginOTP sends a one-time code and verifies the code read from stdin.
+func loginOTP(client *supabase.Client, email, phone string, stdout io.Writer) (*supabase.Session, error) {
+	body := map[string]any{"email": email, "phone": phone, "create_user": false}
+	if _, err := authCall(client, "otp", body); err != nil {
+		return nil, err
+	}
+	code, err := prompt(stdout, "Enter the code we sent you: ")
+	if err != nil {
+		return nil, err
+	}
+	verifyType := "email"
+	if phone != "" {
+		verifyType = "sms"
+	}
+	return signIn(client, "verify", map[string]string{
+		"email": email, "phone": phone, "token": code, "type": verifyType,
+	})
+}
+
+// loginPKCE runs the OAuth PKCE flow: it opens the provider's authorize page
+// with a redirect to a local callback server and exchanges the returned code.
+// The redirect carries a random state that the callback must echo, so codes
+// delivered to the local server by anything but this login are ignored. The
+// callback URL must be allowed in the project's redirect URL settings.
+func loginPKCE(client *supabase.Client, provider string, stdout io.Writer) (*supabase.Session, error) {
+	verifier, challenge := pkcePair()
+	state := randomToken()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+	redirect := fmt.Sprintf("http://%s/callback?%s", listener.Addr(), url.Values{"state": {state}}.Encode())
+
+	codes := make(chan string, 1)
+	server := &http.Server{Handler: pkceCallback(state, codes)}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
+	authorize := fmt.Sprintf("%s/authorize?%s", client.ServiceURL(supabase.ServiceAuth), url.Values{
+		"provider":              {provider},
+		"redirect_to":           {redirect},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"s256"},
+	}.Encode())
+	fmt.Fprintf(stdout, "Open this URL to log in:\n\n  %s\n\n", authorize)
+	openBrowser(authorize)
+
+	select {
+	case code := <-codes:
+		return signIn(client, "token?grant_type=pkce", map[string]string{
+			"auth_code": code, "code_verifier": verifier,
+		})
+	case <-time.After(5 * time.Minute):
+		return nil, errors.New("timed out waiting for the browser login")
+	}
+}
+
+// pkceCallback handles the OAuth redirect, passing the code to codes when the
+// request carries state.
+func pkceCallback(state string, codes chan<- string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.URL.Query().Get("state")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(state)) != 1 {
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		}
+		code := r.URL.Query().Get("code")
+		if code == "" {
+			http.Error(w, "missing code", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, "Logged in. You can close this window.")
+		select {
+		case codes <- code:
+		default:
+		}
+	})
+}
+
+// pkcePair returns a random code verifier and its S256 challenge.
+func pkcePair() (verifier, challenge string) {
+	verifier = randomToken()
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// randomToken returns 32 random bytes encoded as unpadded base64url.
+func randomToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// openBrowser tries to open u in the user's browser, ignoring failures.
+func openBrowser(u string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	_ = cmd.Start()
+}
+
+// authClient returns a client for the auth endpoints of projectURL that
+// sends only the API key.
+func authClient(projectURL, key string) *supabase.Client {
+	return supabase.NewClient(projectURL, key, "")
+}
+
+// signIn calls an auth endpoint that returns a session.
+func signIn(client *supabase.Client, path string, body any) (*supabase.Session, error) {
+	data, err := authCall(client, path, body)
+	if err != nil {
+		return nil, err
+	}
+	var s supabase.Session
+	if err := json.Unmarshal(data, &s); err != nil || s.AccessToken == "" {
+		return nil, fmt.Errorf("unexpected auth response")
+	}
+	return &s, nil
+}
+
+// authCall POSTs body to an auth endpoint and returns the response body.
+// Errors carry the server's message only, so credentials echoed back by the
+// server are never printed.
+func authCall(client *supabase.Client, path string, body any) ([]byte, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(context.Background(), http.MethodPost, authPath(client, path), nil, nil, payload)
+	if err != nil {
+		return nil, fmt.Errorf("login failed: %w", err)
+	}
+	return resp.Body, nil
+}
+
+// authPath returns the path of an auth endpoint relative to the project URL,
+// as Do expects, taken from the same auth service URL loginPKCE opens.
+func authPath(client *supabase.Client, path string) string {
+	base := strings.TrimPrefix(client.ServiceURL(supabase.ServiceAuth), strings.TrimSuffix(client.BaseUrl, "/"))
+	return base + "/" + path
+}
+
+// prompt prints message and reads a line from stdin.
+func prompt(stdout io.Writer, message string) (string, error) {
+	fmt.Fprint(stdout, message)
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read input: %v", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// readPassword prints message and reads a line from stdin like prompt, with
+// terminal echo turned off while it is typed.
+func readPassword(stdout io.Writer, message string) (string, error) {
+	f, ok := stdin.(*os.File)
+	if !ok || !isTerminal(f) {
+		return prompt(stdout, message)
+	}
+	if err := stty(f, "-echo"); err != nil {
+		return "", fmt.Errorf("failed to hide password input (%v); set %s instead", err, passwordEnv)
+	}
+	defer func() {
+		_ = stty(f, "echo")
+		fmt.Fprintln(stdout)
+	}()
+	return prompt(stdout, message)
+}
+
+// isTerminal reports whether f is a character device such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// stty applies setting to the terminal f.
+func stty(f *os.File, setting string) error {
+	cmd := exec.Command("stty", setting)
+	cmd.Stdin = f
+	return cmd.Run()
+}
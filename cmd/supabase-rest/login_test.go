@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtclarkjr/supabase-go-rest"
+	"github.com/jtclarkjr/supabase-go-rest/supabasetest"
+)
+
+func loginEnv(t *testing.T, url string) func(string) string {
+	dir := t.TempDir()
+	env := testEnv(url)
+	return func(key string) string {
+		if key == configDirEnv {
+			return dir
+		}
+		return env(key)
+	}
+}
+
+func setStdin(t *testing.T, input string) {
+	old := stdin
+	stdin = strings.NewReader(input)
+	t.Cleanup(func() { stdin = old })
+}
+
+func TestLoginPassword(t *testing.T) {
+	server := supabasetest.NewServer()
+	defer server.Close()
+	server.AddUser(supabasetest.User{Email: "ann@example.com", Password: "hunter22"})
+	getenv := loginEnv(t, server.URL)
+	setStdin(t, "hunter22\n")
+
+	var out bytes.Buffer
+	if err := run([]string{"login", "--email", "ann@example.com"}, &out, getenv); err != nil {
+		t.Fatalf("login returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Logged in as ann@example.com") {
+		t.Errorf("Expected login confirmation, got %q", out.String())
+	}
+
+	token, err := storedToken(getenv, server.URL, supabasetest.APIKey)
+	if err != nil || token == "" {
+		t.Fatalf("Expected stored token, got %q, %v", token, err)
+	}
+
+	if err := run([]string{"logout"}, &out, getenv); err != nil {
+		t.Fatalf("logout returned error: %v", err)
+	}
+	if token, _ := storedToken(getenv, server.URL, supabasetest.APIKey); token != "" {
+		t.Errorf("Expected no token after logout, got %q", token)
+	}
+}
+
+func TestLoginPasswordInvalid(t *testing.T) {
+	server := supabasetest.NewServer()
+	defer server.Close()
+	server.AddUser(supabasetest.User{Email: "ann@example.com", Password: "hunter22"})
+
+	env := loginEnv(t, server.URL)
+	getenv := func(key string) string {
+		if key == passwordEnv {
+			return "wrong"
+		}
+		return env(key)
+	}
+	err := run([]string{"login", "--email", "ann@example.com"}, &bytes.Buffer{}, getenv)
+	if err == nil || !strings.Contains(err.Error(), "Invalid login credentials") {
+		t.Errorf("Expected invalid credentials error, got %v", err)
+	}
+
+	// Passwords are not accepted as flags, where ps and shell history would
+	// show them.
+	if err := run([]string{"login", "--email", "ann@example.com", "--password", "hunter22"}, &bytes.Buffer{}, env); err == nil {
+		t.Error("Expected --password to be rejected")
+	}
+}
+
+func TestLoginOTP(t *testing.T) {
+	server := supabasetest.NewServer()
+	defer server.Close()
+	server.AddUser(supabasetest.User{Email: "ann@example.com"})
+	getenv := loginEnv(t, server.URL)
+	setStdin(t, supabasetest.OTPCode+"\n")
+
+	var out bytes.Buffer
+	if err := run([]string{"login", "--email", "ann@example.com", "--otp"}, &out, getenv); err != nil {
+		t.Fatalf("login returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Enter the code") {
+		t.Errorf("Expected code prompt, got %q", out.String())
+	}
+	if token, _ := storedToken(getenv, server.URL, supabasetest.APIKey); token == "" {
+		t.Error("Expected stored token after OTP login")
+	}
+}
+
+func TestStoredTokenRefresh(t *testing.T) {
+	server := supabasetest.NewServer()
+	defer server.Close()
+	server.AddUser(supabasetest.User{Email: "ann@example.com", Password: "hunter22"})
+	server.SetTokenTTL(time.Second)
+	getenv := loginEnv(t, server.URL)
+	setStdin(t, "hunter22\n")
+
+	if err := run([]string{"login", "--email", "ann@example.com"}, &bytes.Buffer{}, getenv); err != nil {
+		t.Fatalf("login returned error: %v", err)
+	}
+	sessions, _ := loadSessions(getenv)
+	first := sessions[server.URL]
+
+	server.SetTokenTTL(time.Hour)
+	token, err := storedToken(getenv, server.URL, supabasetest.APIKey)
+	if err != nil {
+		t.Fatalf("storedToken returned error: %v", err)
+	}
+	if token == first.AccessToken {
+		t.Error("Expected expired session to be refreshed")
+	}
+	sessions, _ = loadSessions(getenv)
+	if sessions[server.URL].RefreshToken == first.RefreshToken {
+		t.Error("Expected rotated refresh token to be saved")
+	}
+
+	// Reusing the old refresh token is rejected, so the user must log in again.
+	_ = saveSession(getenv, server.URL, &first)
+	if _, err := storedToken(getenv, server.URL, supabasetest.APIKey); err == nil || !strings.Contains(err.Error(), "supabase-rest login") {
+		t.Errorf("Expected login prompt error, got %v", err)
+	}
+}
+
+func TestAuthPath(t *testing.T) {
+	client := supabase.NewClient("https://example.supabase.co/", "key", "")
+	if got := authPath(client, "token?grant_type=password"); got != "/auth/v1/token?grant_type=password" {
+		t.Errorf("Expected the default auth path, got %q", got)
+	}
+	client = supabase.NewClient("https://example.supabase.co", "key", "", supabase.WithServicePath(supabase.ServiceAuth, "/gotrue"))
+	if got := authPath(client, "otp"); got != "/gotrue/otp" {
+		t.Errorf("Expected the configured auth path, got %q", got)
+	}
+}
+
+func TestPKCEPair(t *testing.T) {
+	verifier, challenge := pkcePair()
+	if len(verifier) < 43 || challenge == "" || verifier == challenge {
+		t.Errorf("Unexpected PKCE pair %q, %q", verifier, challenge)
+	}
+}
+
+func TestPKCECallback(t *testing.T) {
+	codes := make(chan string, 1)
+	handler := pkceCallback("expected-state", codes)
+
+	for _, target := range []string{"/callback?code=abc", "/callback?state=forged&code=abc", "/callback?state=expected-state"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected %s to be rejected, got %d", target, rec.Code)
+		}
+	}
+	if len(codes) != 0 {
+		t.Fatal("Expected no code from rejected callbacks")
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/callback?state=expected-state&code=abc", nil))
+	if rec.Code != http.StatusOK || <-codes != "abc" {
+		t.Errorf("Expected the code to be accepted, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jtclarkjr/supabase-go-rest/supabasetest"
+)
+
+func testEnv(url string) func(string) string {
+	env := map[string]string{
+		"SUPABASE_URL":      url,
+		"SUPABASE_ANON_KEY": supabasetest.APIKey,
+	}
+	return func(key string) string { return env[key] }
+}
+
+func TestGet(t *testing.T) {
+	server := supabasetest.NewServer()
+	defer server.Close()
+	server.Seed("Food",
+		supabasetest.Row{"id": 1, "food_name": "Ramen", "rating": 5, "opinion": nil},
+		supabasetest.Row{"id": 2, "food_name": "Udon", "rating": 3, "opinion": "ok, fine"},
+		supabasetest.Row{"id": 3, "food_name": "Soba", "rating": 5, "opinion": "good"},
+	)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{
+			[]string{"get", "Food", "--eq", "rating=5", "--select", "id,food_name", "--order", "id.desc", "--format", "table"},
+			"id  food_name\n3   Soba\n1   Ramen\n",
+		},
+		{
+			[]string{"get", "Food", "--filter", "rating=lt.5", "--format", "csv"},
+			"food_name,id,opinion,rating\nUdon,2,\"ok, fine\",3\n",
+		},
+		{
+			[]string{"get", "Food", "--eq", "id=1", "--select", "name:food_name"},
+			"[\n  {\n    \"name\": \"Ramen\"\n  }\n]\n",
+		},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := run(tt.args, &out, testEnv(server.URL)); err != nil {
+			t.Errorf("run(%v) returned error: %v", tt.args, err)
+			continue
+		}
+		if out.String() != tt.want {
+			t.Errorf("run(%v) printed:\n%s\nwant:\n%s", tt.args, out.String(), tt.want)
+		}
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		env  func(string) string
+		want string
+	}{
+		{[]string{}, testEnv("http://localhost"), "missing command"},
+		{[]string{"drop", "Food"}, testEnv("http://localhost"), "unknown command"},
+		{[]string{"get"}, testEnv("http://localhost"), "missing table name"},
+		{[]string{"get", "Food", "--eq", "rating"}, testEnv("http://localhost"), "invalid --eq"},
+		{[]string{"get", "Food"}, func(string) string { return "" }, "SUPABASE_URL"},
+	}
+	for _, tt := range tests {
+		err := run(tt.args, &bytes.Buffer{}, tt.env)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("run(%v) = %v, want error containing %q", tt.args, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package supabase
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Codec encodes and decodes JSON payloads. It allows replacing encoding/json
+// with a faster implementation such as goccy/go-json or sonic.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// jsonCodec is the default Codec backed by encoding/json.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// WithCodec sets the Codec used by the client to encode request values and
+// decode responses.
+func WithCodec(codec Codec) Option {
+	return func(c *Client) {
+		c.codec = codec
+	}
+}
+
+// getCodec returns the configured Codec, defaulting to encoding/json.
+func (c *Client) getCodec() Codec {
+	if c.codec != nil {
+		return c.codec
+	}
+	return jsonCodec{}
+}
+
+// GetInto performs a GET request like Get and decodes the response into dst.
+func (c *Client) GetInto(endpoint string, dst any, queryParams ...map[string]string) error {
+	body, err := c.Get(endpoint, queryParams...)
+	if err != nil {
+		return err
+	}
+	if err := c.getCodec().Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("failed to decode response: %v", err)
+	}
+	return nil
+}
+
+// PostJSON encodes v and performs a POST request with it like Post.
+func (c *Client) PostJSON(endpoint string, v any) ([]byte, error) {
+	data, err := c.getCodec().Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %v", err)
+	}
+	return c.Post(endpoint, data)
+}
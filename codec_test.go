@@ -0,0 +1,83 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type countingCodec struct {
+	marshals, unmarshals int
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshals++
+	return json.Unmarshal(data, v)
+}
+
+func TestWithCodec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			w.Write(body)
+			return
+		}
+		w.Write([]byte(`[{"id":1,"food_name":"Ramen"}]`))
+	}))
+	defer server.Close()
+
+	codec := &countingCodec{}
+	client := NewClient(server.URL, "your_api_key", "your_token", WithCodec(codec))
+
+	var foods []struct {
+		ID       int    `json:"id"`
+		FoodName string `json:"food_name"`
+	}
+	if err := client.GetInto("Food", &foods); err != nil {
+		t.Fatalf("GetInto returned error: %v", err)
+	}
+	if len(foods) != 1 || foods[0].FoodName != "Ramen" {
+		t.Errorf("Unexpected decoded result: %+v", foods)
+	}
+
+	body, err := client.PostJSON("Food", map[string]string{"food_name": "Udon"})
+	if err != nil {
+		t.Fatalf("PostJSON returned error: %v", err)
+	}
+	if string(body) != `{"food_name":"Udon"}` {
+		t.Errorf("Unexpected request body %s", body)
+	}
+
+	if codec.marshals != 1 || codec.unmarshals != 1 {
+		t.Errorf("Expected custom codec to be used, got %d marshals and %d unmarshals", codec.marshals, codec.unmarshals)
+	}
+}
+
+func TestWithCodecWrites(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":1}]`))
+	}))
+	defer server.Close()
+
+	codec := &countingCodec{}
+	client := NewClient(server.URL, "your_api_key", "your_token", WithCodec(codec))
+	ctx := context.Background()
+
+	if _, err := client.UpdateIfVersion(ctx, "Food", Eq("id", 1), Eq("version", 3), []byte(`{"name":"Udon"}`)); err != nil {
+		t.Fatalf("UpdateIfVersion returned error: %v", err)
+	}
+	if _, _, err := UpdateWhereIn(ctx, client, "Food", "id", []int{1}, []byte(`{"name":"Udon"}`)); err != nil {
+		t.Fatalf("UpdateWhereIn returned error: %v", err)
+	}
+	if codec.unmarshals != 2 {
+		t.Errorf("Expected writes to decode with the codec, got %d unmarshals", codec.unmarshals)
+	}
+}
@@ -0,0 +1,128 @@
+package supabase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config holds client settings for services that centralize configuration.
+// It unmarshals from JSON, and from YAML with libraries that honour yaml tags
+// and encoding.TextUnmarshaler, such as gopkg.in/yaml.v3:
+//
+//	{
+//	  "url": "https://xyz.supabase.co",
+//	  "anon_key": "...",
+//	  "timeout": "10s",
+//	  "retry": {"max_attempts": 3, "min_backoff": "100ms", "max_backoff": "2s"},
+//	  "schema": "api"
+//	}
+type Config struct {
+	URL            string `json:"url" yaml:"url"`
+	AnonKey        string `json:"anon_key" yaml:"anon_key"`
+	ServiceRoleKey string `json:"service_role_key,omitempty" yaml:"service_role_key,omitempty"`
+	// Proxy is an HTTP proxy URL.
+	Proxy string `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	// Timeout limits each request; zero means no limit.
+	Timeout Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// MaxResponseSize limits response bodies in bytes; zero means no limit.
+	MaxResponseSize int64        `json:"max_response_size,omitempty" yaml:"max_response_size,omitempty"`
+	Retry           *RetryConfig `json:"retry,omitempty" yaml:"retry,omitempty"`
+	// Schema selects a Postgres schema other than the default exposed one.
+	Schema string `json:"schema,omitempty" yaml:"schema,omitempty"`
+}
+
+// RetryConfig is the configuration form of RetryPolicy.
+type RetryConfig struct {
+	MaxAttempts int      `json:"max_attempts" yaml:"max_attempts"`
+	MinBackoff  Duration `json:"min_backoff" yaml:"min_backoff"`
+	MaxBackoff  Duration `json:"max_backoff" yaml:"max_backoff"`
+}
+
+// Duration is a time.Duration written as a string such as "1m30s" in
+// configuration files.
+type Duration time.Duration
+
+// MarshalText encodes d in time.Duration.String form.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText parses a duration accepted by time.ParseDuration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// LoadConfig reads a JSON configuration file.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("supabase: invalid config %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// NewClientFromConfig creates a client from cfg. The anon key is used when
+// set, with the service role key available through AsServiceRole, and the
+// service role key otherwise. opts are applied after the settings from cfg.
+func NewClientFromConfig(cfg Config, opts ...Option) (*Client, error) {
+	key, serviceRoleKey := cfg.AnonKey, cfg.ServiceRoleKey
+	if key == "" {
+		key, serviceRoleKey = serviceRoleKey, ""
+	}
+	var missing []string
+	if cfg.URL == "" {
+		missing = append(missing, "url")
+	}
+	if key == "" {
+		missing = append(missing, "anon_key or service_role_key")
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("supabase: config is missing %s", strings.Join(missing, ", "))
+	}
+
+	var cfgOpts []Option
+	if serviceRoleKey != "" {
+		cfgOpts = append(cfgOpts, WithServiceRoleKey(serviceRoleKey))
+	}
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("supabase: invalid proxy %q in config", cfg.Proxy)
+		}
+		cfgOpts = append(cfgOpts, WithProxy(proxyURL))
+	}
+	if cfg.Timeout < 0 || cfg.MaxResponseSize < 0 {
+		return nil, errors.New("supabase: timeout and max_response_size must not be negative")
+	}
+	if cfg.Timeout > 0 {
+		cfgOpts = append(cfgOpts, WithTimeout(time.Duration(cfg.Timeout)))
+	}
+	if cfg.MaxResponseSize > 0 {
+		cfgOpts = append(cfgOpts, WithMaxResponseSize(cfg.MaxResponseSize))
+	}
+	if r := cfg.Retry; r != nil {
+		cfgOpts = append(cfgOpts, WithRetryPolicy(RetryPolicy{
+			MaxAttempts: r.MaxAttempts,
+			MinBackoff:  time.Duration(r.MinBackoff),
+			MaxBackoff:  time.Duration(r.MaxBackoff),
+		}))
+	}
+	if cfg.Schema != "" {
+		cfgOpts = append(cfgOpts, WithSchema(cfg.Schema))
+	}
+	return NewClient(cfg.URL, key, "Bearer "+key, append(cfgOpts, opts...)...), nil
+}
@@ -0,0 +1,73 @@
+package supabase
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "supabase.json")
+	os.WriteFile(path, []byte(`{
+		"url": "https://example.supabase.co",
+		"anon_key": "anon",
+		"timeout": "10s",
+		"max_response_size": 1048576,
+		"retry": {"max_attempts": 5, "min_backoff": "50ms", "max_backoff": "1s"},
+		"schema": "api"
+	}`), 0o600)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	c, err := NewClientFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewClientFromConfig returned error: %v", err)
+	}
+	if c.BaseUrl != "https://example.supabase.co" || c.ApiKey != "anon" || c.httpClient.Timeout != 10*time.Second {
+		t.Errorf("Unexpected client %s %s %v", c.BaseUrl, c.ApiKey, c.httpClient.Timeout)
+	}
+	want := RetryPolicy{MaxAttempts: 5, MinBackoff: 50 * time.Millisecond, MaxBackoff: time.Second}
+	if c.retryPolicy == nil || *c.retryPolicy != want || c.maxResponseSize != 1<<20 {
+		t.Errorf("Unexpected retry policy %+v or max size %d", c.retryPolicy, c.maxResponseSize)
+	}
+
+	get, _ := c.prepare("GET", "Food", nil, nil, nil)
+	post, _ := c.prepare("POST", "Food", nil, nil, nil)
+	if get.Header.Get("Accept-Profile") != "api" || post.Header.Get("Content-Profile") != "api" {
+		t.Errorf("Expected schema profile headers, got %v and %v", get.Header, post.Header)
+	}
+}
+
+func TestNewClientFromConfigErrors(t *testing.T) {
+	tests := []struct {
+		cfg  Config
+		want string
+	}{
+		{Config{}, "config is missing url, anon_key or service_role_key"},
+		{Config{URL: "u", AnonKey: "k", Proxy: "::"}, "invalid proxy"},
+		{Config{URL: "u", AnonKey: "k", Timeout: -1}, "must not be negative"},
+	}
+	for _, tt := range tests {
+		if _, err := NewClientFromConfig(tt.cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected error containing %q, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestDurationJSON(t *testing.T) {
+	var d Duration
+	if err := json.Unmarshal([]byte(`"1m30s"`), &d); err != nil || time.Duration(d) != 90*time.Second {
+		t.Errorf("Expected 1m30s, got %v, %v", time.Duration(d), err)
+	}
+	if data, _ := json.Marshal(d); string(data) != `"1m30s"` {
+		t.Errorf("Expected \"1m30s\", got %s", data)
+	}
+	if err := json.Unmarshal([]byte(`"soon"`), &d); err == nil {
+		t.Error("Expected error for invalid duration")
+	}
+}
@@ -0,0 +1,183 @@
+package supabase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoSession is returned when a request carries no session cookie.
+var ErrNoSession = errors.New("supabase: no session cookie")
+
+// ErrSessionExpired is returned by ClientFromRequest when the session's
+// access token has expired and must be refreshed.
+var ErrSessionExpired = errors.New("supabase: session expired")
+
+// Session cookies use the format of @supabase/ssr, so sessions are shared
+// with supabase-js code on the same site: the JSON session is base64url
+// encoded behind a "base64-" prefix and split into name.0, name.1, ... when
+// it exceeds the chunk size.
+const (
+	sessionCookiePrefix    = "base64-"
+	sessionCookieChunkSize = 3180
+)
+
+// defaultSessionCookie is the template for session cookies.
+var defaultSessionCookie = http.Cookie{
+	Path:     "/",
+	MaxAge:   400 * 24 * 60 * 60,
+	HttpOnly: true,
+	Secure:   true,
+	SameSite: http.SameSiteLaxMode,
+}
+
+// WithSessionCookie sets the attributes of session cookies written by
+// SetSessionCookies; Name and Value are ignored. By default cookies are
+// HttpOnly, Secure, SameSite=Lax, scoped to "/", and kept for 400 days. Clear
+// HttpOnly if browser code using supabase-js must read the session too.
+func WithSessionCookie(template http.Cookie) Option {
+	return func(c *Client) {
+		c.sessionCookie = &template
+	}
+}
+
+// SessionCookieName returns the cookie name used for the project's session,
+// sb-<project-ref>-auth-token, as supabase-js derives it from the URL.
+func (c *Client) SessionCookieName() string {
+	ref := "localhost"
+	if u, err := url.Parse(c.BaseUrl); err == nil && u.Hostname() != "" {
+		ref, _, _ = strings.Cut(u.Hostname(), ".")
+	}
+	return "sb-" + ref + "-auth-token"
+}
+
+// SetSessionCookies writes s to session cookies on w, chunked when large.
+// Cookies from a previous, larger session found on r are expired.
+func (c *Client) SetSessionCookies(w http.ResponseWriter, r *http.Request, s *Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %v", err)
+	}
+	value := sessionCookiePrefix + base64.RawURLEncoding.EncodeToString(data)
+	name := c.SessionCookieName()
+
+	written := map[string]bool{}
+	if len(value) <= sessionCookieChunkSize {
+		c.setCookie(w, name, value, 0)
+		written[name] = true
+	} else {
+		for i := 0; len(value) > 0; i++ {
+			n := min(len(value), sessionCookieChunkSize)
+			chunk := name + "." + strconv.Itoa(i)
+			c.setCookie(w, chunk, value[:n], 0)
+			written[chunk] = true
+			value = value[n:]
+		}
+	}
+	if r != nil {
+		for _, cookie := range sessionCookies(r, name) {
+			if !written[cookie.Name] {
+				c.setCookie(w, cookie.Name, "", -1)
+			}
+		}
+	}
+	return nil
+}
+
+// ClearSessionCookies expires the session cookies found on r.
+func (c *Client) ClearSessionCookies(w http.ResponseWriter, r *http.Request) {
+	for _, cookie := range sessionCookies(r, c.SessionCookieName()) {
+		c.setCookie(w, cookie.Name, "", -1)
+	}
+}
+
+// SessionFromRequest reads the session from the cookies of r, joining
+// chunks. It returns ErrNoSession when there is none.
+func (c *Client) SessionFromRequest(r *http.Request) (*Session, error) {
+	name := c.SessionCookieName()
+	var value string
+	if cookie, err := r.Cookie(name); err == nil {
+		value = cookie.Value
+	} else {
+		var b strings.Builder
+		for i := 0; ; i++ {
+			cookie, err := r.Cookie(name + "." + strconv.Itoa(i))
+			if err != nil {
+				break
+			}
+			b.WriteString(cookie.Value)
+		}
+		value = b.String()
+	}
+	if value == "" {
+		return nil, ErrNoSession
+	}
+
+	var data []byte
+	if encoded, ok := strings.CutPrefix(value, sessionCookiePrefix); ok {
+		var err error
+		if data, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "=")); err != nil {
+			return nil, fmt.Errorf("failed to decode session cookie: %v", err)
+		}
+	} else {
+		// Older @supabase/ssr versions stored URI-encoded JSON.
+		unescaped, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode session cookie: %v", err)
+		}
+		data = []byte(unescaped)
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil || s.AccessToken == "" {
+		return nil, errors.New("failed to decode session cookie: invalid session")
+	}
+	return &s, nil
+}
+
+// ClientFromRequest returns a clone of the client authenticated with the
+// session in the cookies of r, for server-rendered apps. It returns
+// ErrNoSession without a session and ErrSessionExpired, together with the
+// unauthenticated client, when the access token has expired.
+func (c *Client) ClientFromRequest(r *http.Request) (*Client, error) {
+	s, err := c.SessionFromRequest(r)
+	if err != nil {
+		return c, err
+	}
+	if s.Expired(0) {
+		return c, ErrSessionExpired
+	}
+	return c.WithSession(s), nil
+}
+
+// setCookie writes a session cookie using the configured template. A
+// negative maxAge deletes the cookie.
+func (c *Client) setCookie(w http.ResponseWriter, name, value string, maxAge int) {
+	cookie := defaultSessionCookie
+	if c.sessionCookie != nil {
+		cookie = *c.sessionCookie
+	}
+	cookie.Name = name
+	cookie.Value = value
+	if maxAge < 0 {
+		cookie.MaxAge = -1
+		cookie.Expires = time.Unix(0, 0)
+	}
+	http.SetCookie(w, &cookie)
+}
+
+// sessionCookies returns the session cookies on r, chunked or not.
+func sessionCookies(r *http.Request, name string) []*http.Cookie {
+	var cookies []*http.Cookie
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name || strings.HasPrefix(cookie.Name, name+".") {
+			cookies = append(cookies, cookie)
+		}
+	}
+	return cookies
+}
@@ -0,0 +1,112 @@
+package supabase
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// requestWithCookies returns a request carrying the cookies set on rec.
+func requestWithCookies(rec *httptest.ResponseRecorder) *http.Request {
+	r := httptest.NewRequest("GET", "/", nil)
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.MaxAge >= 0 {
+			r.AddCookie(cookie)
+		}
+	}
+	return r
+}
+
+func TestSessionCookies(t *testing.T) {
+	client := NewClient("https://abcdefgh.supabase.co", "key", "")
+	if got := client.SessionCookieName(); got != "sb-abcdefgh-auth-token" {
+		t.Errorf("Unexpected cookie name %s", got)
+	}
+
+	session := &Session{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+		User:         &User{ID: "u1", Email: "ann@example.com"},
+	}
+	rec := httptest.NewRecorder()
+	if err := client.SetSessionCookies(rec, nil, session); err != nil {
+		t.Fatalf("SetSessionCookies returned error: %v", err)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !strings.HasPrefix(cookies[0].Value, "base64-") || !cookies[0].HttpOnly || !cookies[0].Secure {
+		t.Fatalf("Unexpected cookies %v", cookies)
+	}
+
+	r := requestWithCookies(rec)
+	got, err := client.SessionFromRequest(r)
+	if err != nil || got.AccessToken != "access" || got.User.Email != "ann@example.com" {
+		t.Fatalf("Expected session round trip, got %+v, %v", got, err)
+	}
+	authed, err := client.ClientFromRequest(r)
+	if err != nil || authed.Token != "Bearer access" || client.Token != "" {
+		t.Errorf("Expected authenticated clone, got %q, %v", authed.Token, err)
+	}
+}
+
+func TestSessionCookiesChunked(t *testing.T) {
+	client := NewClient("https://abcdefgh.supabase.co", "key", "")
+	session := &Session{
+		AccessToken: "access",
+		User:        &User{ID: "u1", UserMetadata: map[string]any{"bio": strings.Repeat("x", 5000)}},
+	}
+	rec := httptest.NewRecorder()
+	client.SetSessionCookies(rec, nil, session)
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 3 || cookies[0].Name != "sb-abcdefgh-auth-token.0" || cookies[2].Name != "sb-abcdefgh-auth-token.2" {
+		t.Fatalf("Expected 3 chunks, got %d", len(cookies))
+	}
+	for _, cookie := range cookies {
+		if len(cookie.Value) > sessionCookieChunkSize {
+			t.Errorf("Chunk %s is %d bytes", cookie.Name, len(cookie.Value))
+		}
+	}
+	r := requestWithCookies(rec)
+	got, err := client.SessionFromRequest(r)
+	if err != nil || got.User.UserMetadata["bio"] != strings.Repeat("x", 5000) {
+		t.Fatalf("Expected chunked session round trip, got %v", err)
+	}
+
+	// A smaller session replaces the chunks with a single cookie.
+	rec = httptest.NewRecorder()
+	client.SetSessionCookies(rec, r, &Session{AccessToken: "small"})
+	expired := 0
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.MaxAge < 0 {
+			expired++
+		}
+	}
+	if expired != 3 {
+		t.Errorf("Expected 3 stale chunks to be expired, got %d", expired)
+	}
+}
+
+func TestSessionFromRequestErrors(t *testing.T) {
+	client := NewClient("http://127.0.0.1:54321", "key", "")
+
+	r := httptest.NewRequest("GET", "/", nil)
+	if _, err := client.ClientFromRequest(r); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Expected ErrNoSession, got %v", err)
+	}
+
+	r = httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "sb-127-auth-token", Value: url.QueryEscape(`{"access_token":"a","expires_at":1}`)})
+	if c, err := client.ClientFromRequest(r); !errors.Is(err, ErrSessionExpired) || c.Token != "" {
+		t.Errorf("Expected ErrSessionExpired for legacy cookie, got %v", err)
+	}
+
+	r = httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(&http.Cookie{Name: "sb-127-auth-token", Value: "base64-!!!"})
+	if _, err := client.SessionFromRequest(r); err == nil {
+		t.Error("Expected error for malformed cookie")
+	}
+}
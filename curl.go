@@ -0,0 +1,112 @@
+package supabase
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// curlSecretHeaders are replaced with shell variables in ToCurl output, so
+// the command still works once the variables are exported.
+var curlSecretHeaders = map[string]string{
+	"Apikey":        "$SUPABASE_ANON_KEY",
+	"Authorization": "Bearer $SUPABASE_TOKEN",
+}
+
+// curlMaskedHeaders are replaced with a placeholder in ToCurl output.
+var curlMaskedHeaders = map[string]bool{
+	"Cookie":    true,
+	"X-Api-Key": true,
+}
+
+// ToCurl renders the request as a copy-pastable curl command for reproducing
+// issues outside of Go. Credentials are masked: the API key and token become
+// $SUPABASE_ANON_KEY and $SUPABASE_TOKEN, and JSON body fields that look like
+// secrets, such as passwords and tokens, are replaced with "***".
+func (p *PreparedRequest) ToCurl() string {
+	parts := []string{"curl"}
+	if p.Method != http.MethodGet {
+		parts = append(parts, "-X "+p.Method)
+	}
+	parts = append(parts, shellQuote(p.URL.String()))
+
+	keys := make([]string, 0, len(p.Header))
+	for key := range p.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		canonical := http.CanonicalHeaderKey(key)
+		for _, value := range p.Header[key] {
+			switch {
+			case curlSecretHeaders[canonical] != "":
+				parts = append(parts, "-H "+`"`+key+": "+curlSecretHeaders[canonical]+`"`)
+			case curlMaskedHeaders[canonical]:
+				parts = append(parts, "-H "+shellQuote(key+": ***"))
+			default:
+				parts = append(parts, "-H "+shellQuote(key+": "+value))
+			}
+		}
+	}
+	if len(p.Body) > 0 {
+		parts = append(parts, "--data-raw "+shellQuote(string(maskBody(p.Body))))
+	}
+	return strings.Join(parts, " \\\n  ")
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// maskBody replaces secret-looking fields in a JSON body. Bodies that are not
+// JSON are returned unchanged.
+func maskBody(body []byte) []byte {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	if !maskValue(v) {
+		return body
+	}
+	masked, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return masked
+}
+
+// maskValue masks secret fields in v in place and reports whether any were found.
+func maskValue(v any) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if isSecretField(key) {
+				v[key] = "***"
+				found = true
+			} else if maskValue(value) {
+				found = true
+			}
+		}
+	case []any:
+		for _, value := range v {
+			if maskValue(value) {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// isSecretField reports whether a JSON field name looks like it holds a secret.
+func isSecretField(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range []string{"password", "secret", "token", "api_key", "apikey"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
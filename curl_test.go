@@ -0,0 +1,39 @@
+package supabase
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestToCurl(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "anon-key", "Bearer user-jwt")
+	req, err := client.Prepare("POST", "profiles", url.Values{"select": {"id"}}, []byte(`{"name":"O'Brien","password":"hunter22","meta":{"refresh_token":"r"}}`))
+	if err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+
+	got := req.ToCurl()
+	want := `curl \
+  -X POST \
+  'https://example.supabase.co/rest/v1/profiles?select=id' \
+  -H "Apikey: $SUPABASE_ANON_KEY" \
+  -H "Authorization: Bearer $SUPABASE_TOKEN" \
+  -H 'Content-Type: application/json' \
+  --data-raw '{"meta":{"refresh_token":"***"},"name":"O'\''Brien","password":"***"}'`
+	if got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+	for _, secret := range []string{"anon-key", "user-jwt", "hunter22"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("Expected %q to be masked", secret)
+		}
+	}
+}
+
+func TestToCurlGet(t *testing.T) {
+	req, _ := NewClient("https://example.supabase.co", "k", "t").From("Food").Eq("id", 1).Prepare()
+	if got := req.ToCurl(); !strings.HasPrefix(got, "curl \\\n  'https://example.supabase.co/rest/v1/Food?id=eq.1'") {
+		t.Errorf("Unexpected curl command:\n%s", got)
+	}
+}
@@ -0,0 +1,162 @@
+package supabase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Enum describes a Postgres enum type so values can be checked before they
+// reach the database, which would otherwise reject them with error 22P02.
+type Enum struct {
+	Name   string
+	Values []string
+}
+
+// NewEnum returns the enum type name with the given labels. Labels may be of
+// any string type, so Go constants can be registered directly:
+//
+//	type Status string
+//
+//	const (
+//		StatusOpen   Status = "open"
+//		StatusClosed Status = "closed"
+//	)
+//
+//	var statusEnum = supabase.NewEnum("ticket_status", StatusOpen, StatusClosed)
+func NewEnum[T ~string](name string, values ...T) *Enum {
+	e := &Enum{Name: name, Values: make([]string, len(values))}
+	for i, v := range values {
+		e.Values[i] = string(v)
+	}
+	return e
+}
+
+// Valid reports whether v is a label of the enum.
+func (e *Enum) Valid(v string) bool {
+	return slices.Contains(e.Values, v)
+}
+
+// EnumError is returned when a filter or write uses a value that is not a
+// label of the column's enum type.
+type EnumError struct {
+	Table  string
+	Column string
+	Enum   *Enum
+	Value  string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("supabase: invalid value %q for %s.%s (enum %s accepts %s)",
+		e.Value, e.Table, e.Column, e.Enum.Name, strings.Join(e.Enum.Values, ", "))
+}
+
+// WithEnumColumn registers column of table as having enum type e. Equality and
+// in filters on the column, and values written to it, are checked before the
+// request is sent and rejected with an *EnumError.
+func WithEnumColumn(table, column string, e *Enum) Option {
+	return func(c *Client) {
+		if c.enums == nil {
+			c.enums = map[string]map[string]*Enum{}
+		}
+		if c.enums[table] == nil {
+			c.enums[table] = map[string]*Enum{}
+		}
+		c.enums[table][column] = e
+	}
+}
+
+// validateEnums checks filters and the JSON body of a request against the
+// enum columns registered for its table.
+func (c *Client) validateEnums(endpoint string, query url.Values, body []byte) error {
+	table, rawQuery, _ := strings.Cut(endpoint, "?")
+	columns := c.enums[table]
+	if len(columns) == 0 {
+		return nil
+	}
+	if rawQuery != "" {
+		extra, err := url.ParseQuery(rawQuery)
+		if err == nil {
+			if err := validateEnumFilters(table, columns, extra); err != nil {
+				return err
+			}
+		}
+	}
+	if err := validateEnumFilters(table, columns, query); err != nil {
+		return err
+	}
+	return validateEnumBody(table, columns, body)
+}
+
+func validateEnumFilters(table string, columns map[string]*Enum, query url.Values) error {
+	for column, e := range columns {
+		for _, filter := range query[column] {
+			filter = strings.TrimPrefix(filter, "not.")
+			op, value, _ := strings.Cut(filter, ".")
+			var values []string
+			switch op {
+			case "eq", "neq":
+				values = []string{value}
+			case "in":
+				values = splitInList(value)
+			}
+			for _, v := range values {
+				if !e.Valid(v) {
+					return &EnumError{Table: table, Column: column, Enum: e, Value: v}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// splitInList splits the value of an in filter such as (a,"b,c") into its items.
+func splitInList(list string) []string {
+	list = strings.TrimSuffix(strings.TrimPrefix(list, "("), ")")
+	var (
+		items   []string
+		current strings.Builder
+		quoted  bool
+	)
+	for i := 0; i < len(list); i++ {
+		switch ch := list[i]; {
+		case ch == '\\' && quoted && i+1 < len(list):
+			i++
+			current.WriteByte(list[i])
+		case ch == '"':
+			quoted = !quoted
+		case ch == ',' && !quoted:
+			items = append(items, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(ch)
+		}
+	}
+	return append(items, current.String())
+}
+
+func validateEnumBody(table string, columns map[string]*Enum, body []byte) error {
+	if len(body) == 0 {
+		return nil
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal(body, &rows); err != nil {
+		var row map[string]any
+		if err := json.Unmarshal(body, &row); err != nil {
+			// Not a JSON row or rows; leave it to the server.
+			return nil
+		}
+		rows = []map[string]any{row}
+	}
+	for _, row := range rows {
+		for column, e := range columns {
+			v, ok := row[column].(string)
+			if ok && !e.Valid(v) {
+				return &EnumError{Table: table, Column: column, Enum: e, Value: v}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package supabase
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+type ticketStatus string
+
+const (
+	statusOpen   ticketStatus = "open"
+	statusClosed ticketStatus = "closed"
+)
+
+func TestEnumValidation(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	status := NewEnum("ticket_status", statusOpen, statusClosed)
+	client := NewClient(server.URL, "key", "token", WithEnumColumn("tickets", "status", status))
+
+	tests := []struct {
+		name    string
+		do      func() error
+		wantErr string
+	}{
+		{"get valid", func() error { _, err := client.Get("tickets", map[string]string{"status": "open"}); return err }, ""},
+		{"get invalid", func() error { _, err := client.Get("tickets", map[string]string{"status": "opne"}); return err }, "opne"},
+		{"in invalid", func() error {
+			_, err := client.Execute("GET", "tickets", url.Values{"status": {`in.(open,"clo,sed")`}}, nil)
+			return err
+		}, "clo,sed"},
+		{"not in valid", func() error {
+			_, err := client.Execute("GET", "tickets?status=not.in.(open,closed)", nil, nil)
+			return err
+		}, ""},
+		{"post invalid", func() error { _, err := client.Post("tickets", []byte(`{"status":"pending"}`)); return err }, "pending"},
+		{"post rows valid", func() error {
+			_, err := client.Post("tickets", []byte(`[{"status":"open"},{"status":null}]`))
+			return err
+		}, ""},
+		{"other table", func() error { _, err := client.Post("notes", []byte(`{"status":"pending"}`)); return err }, ""},
+	}
+	for _, tt := range tests {
+		requests = 0
+		err := tt.do()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		var enumErr *EnumError
+		if !errors.As(err, &enumErr) {
+			t.Errorf("%s: Expected *EnumError, got %v", tt.name, err)
+			continue
+		}
+		if enumErr.Value != tt.wantErr || enumErr.Column != "status" {
+			t.Errorf("%s: Expected invalid value %q, got %+v", tt.name, tt.wantErr, enumErr)
+		}
+		if requests != 0 {
+			t.Errorf("%s: Expected no request to be sent, got %d", tt.name, requests)
+		}
+	}
+}
+
+func TestSplitInList(t *testing.T) {
+	got := splitInList(`(a,"b,c","d\"e")`)
+	want := []string{"a", "b,c", `d"e`}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package supabase
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv.
+const (
+	EnvURL            = "SUPABASE_URL"
+	EnvAnonKey        = "SUPABASE_ANON_KEY"
+	EnvServiceRoleKey = "SUPABASE_SERVICE_ROLE_KEY"
+	EnvProxy          = "SUPABASE_PROXY"
+	EnvTimeout        = "SUPABASE_TIMEOUT"
+)
+
+// NewClientFromEnv creates a client from the environment. SUPABASE_URL and
+// one of SUPABASE_ANON_KEY or SUPABASE_SERVICE_ROLE_KEY are required. When
+// both are set the anon key is used and the service role key is available
+// through AsServiceRole. Requests are authenticated with the key
+// until a user token is attached with WithToken.
+//
+// SUPABASE_PROXY sets an HTTP proxy URL and SUPABASE_TIMEOUT a per-request
+// timeout such as "10s". opts are applied after the environment settings.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	baseURL := os.Getenv(EnvURL)
+	key, serviceRoleKey := os.Getenv(EnvAnonKey), os.Getenv(EnvServiceRoleKey)
+	if key == "" {
+		key, serviceRoleKey = serviceRoleKey, ""
+	}
+
+	var missing []string
+	if baseURL == "" {
+		missing = append(missing, EnvURL)
+	}
+	if key == "" {
+		missing = append(missing, EnvAnonKey+" or "+EnvServiceRoleKey)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("supabase: missing environment variables: %s", strings.Join(missing, ", "))
+	}
+
+	var envOpts []Option
+	if serviceRoleKey != "" {
+		envOpts = append(envOpts, WithServiceRoleKey(serviceRoleKey))
+	}
+	if v := os.Getenv(EnvProxy); v != "" {
+		proxyURL, err := url.Parse(v)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("supabase: invalid %s %q", EnvProxy, v)
+		}
+		envOpts = append(envOpts, WithProxy(proxyURL))
+	}
+	if v := os.Getenv(EnvTimeout); v != "" {
+		timeout, err := time.ParseDuration(v)
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("supabase: invalid %s %q: expected a duration such as 10s", EnvTimeout, v)
+		}
+		envOpts = append(envOpts, WithTimeout(timeout))
+	}
+	return NewClient(baseURL, key, "Bearer "+key, append(envOpts, opts...)...), nil
+}
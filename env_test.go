@@ -0,0 +1,53 @@
+package supabase
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Setenv(EnvURL, "https://example.supabase.co")
+	t.Setenv(EnvAnonKey, "anon")
+	t.Setenv(EnvServiceRoleKey, "service")
+	t.Setenv(EnvProxy, "http://proxy.internal:3128")
+	t.Setenv(EnvTimeout, "5s")
+
+	c, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("NewClientFromEnv returned error: %v", err)
+	}
+	if c.BaseUrl != "https://example.supabase.co" || c.ApiKey != "anon" || c.Token != "Bearer anon" {
+		t.Errorf("Unexpected client %s %s %s", c.BaseUrl, c.ApiKey, c.Token)
+	}
+	if c.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Expected 5s timeout, got %v", c.httpClient.Timeout)
+	}
+	req, _ := http.NewRequest("GET", "https://example.supabase.co", nil)
+	proxy, _ := c.httpClient.Transport.(*http.Transport).Proxy(req)
+	if proxy == nil || proxy.Host != "proxy.internal:3128" {
+		t.Errorf("Expected proxy to be used, got %v", proxy)
+	}
+}
+
+func TestNewClientFromEnvErrors(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want string
+	}{
+		{map[string]string{}, "missing environment variables: SUPABASE_URL, SUPABASE_ANON_KEY or SUPABASE_SERVICE_ROLE_KEY"},
+		{map[string]string{EnvURL: "https://example.supabase.co"}, "missing environment variables: SUPABASE_ANON_KEY or SUPABASE_SERVICE_ROLE_KEY"},
+		{map[string]string{EnvURL: "u", EnvAnonKey: "k", EnvTimeout: "soon"}, "invalid SUPABASE_TIMEOUT"},
+		{map[string]string{EnvURL: "u", EnvAnonKey: "k", EnvProxy: "::"}, "invalid SUPABASE_PROXY"},
+	}
+	for _, tt := range tests {
+		for _, key := range []string{EnvURL, EnvAnonKey, EnvServiceRoleKey, EnvProxy, EnvTimeout} {
+			t.Setenv(key, tt.env[key])
+		}
+		_, err := NewClientFromEnv()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected error containing %q, got %v", tt.want, err)
+		}
+	}
+}
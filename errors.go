@@ -0,0 +1,210 @@
+package supabase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds the limit set
+// with WithMaxResponseSize.
+var ErrResponseTooLarge = errors.New("supabase: response body exceeds maximum size")
+
+// ErrWriteQueued is returned, wrapped together with the underlying failure,
+// when a write could not be delivered and was recorded in the write queue.
+var ErrWriteQueued = errors.New("supabase: write queued for replay")
+
+// ErrPutMismatch is returned by Put when the body is not a single row whose
+// primary key matches the filter. PostgREST rejects such requests.
+var ErrPutMismatch = errors.New("supabase: PUT body does not match primary key filter")
+
+// APIError is returned when Supabase responds with a non-2xx status code.
+//
+// Error never includes credentials: it is built from the message fields of
+// PostgREST and GoTrue error bodies, and other bodies are shown with tokens
+// and secret-looking fields masked. Body holds the raw response and may
+// contain whatever the server echoed back, so avoid logging it.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+
+	// Code, Message, Details, and Hint are parsed from the body when it is
+	// a PostgREST ({code, message, details, hint}) or GoTrue ({error_code,
+	// msg} or {error, error_description}) error.
+	Code    string
+	Message string
+	Details string
+	Hint    string
+}
+
+// Auth failures reported by GoTrue. An *APIError matches them with
+// errors.Is, so handlers can respond accurately:
+//
+//	if errors.Is(err, supabase.ErrUserAlreadyExists) {
+//		http.Error(w, "an account with this email already exists", http.StatusConflict)
+//	}
+var (
+	ErrUserAlreadyExists  = errors.New("supabase: user already exists")
+	ErrInvalidCredentials = errors.New("supabase: invalid login credentials")
+	ErrEmailNotConfirmed  = errors.New("supabase: email not confirmed")
+	// ErrOverRateLimit covers GoTrue's email, SMS, and request rate limits.
+	ErrOverRateLimit = errors.New("supabase: auth rate limit exceeded")
+)
+
+// authErrorCodes maps GoTrue error codes to the errors above. Older GoTrue
+// versions report some failures only as {error: "invalid_grant"} with a
+// message, which authErrorMessages covers.
+var authErrorCodes = map[string]error{
+	"user_already_exists":        ErrUserAlreadyExists,
+	"email_exists":               ErrUserAlreadyExists,
+	"phone_exists":               ErrUserAlreadyExists,
+	"invalid_credentials":        ErrInvalidCredentials,
+	"email_not_confirmed":        ErrEmailNotConfirmed,
+	"over_email_send_rate_limit": ErrOverRateLimit,
+	"over_sms_send_rate_limit":   ErrOverRateLimit,
+	"over_request_rate_limit":    ErrOverRateLimit,
+}
+
+var authErrorMessages = map[string]error{
+	"User already registered":   ErrUserAlreadyExists,
+	"Invalid login credentials": ErrInvalidCredentials,
+	"Email not confirmed":       ErrEmailNotConfirmed,
+}
+
+// Is reports whether the error is the auth failure target, e.g.
+// errors.Is(err, ErrInvalidCredentials).
+func (e *APIError) Is(target error) bool {
+	if err, ok := authErrorCodes[e.Code]; ok {
+		return err == target
+	}
+	return authErrorMessages[e.Message] == target && target != nil
+}
+
+// ErrRowLevelSecurity matches errors for requests that row-level security
+// or missing grants refused: 401 and 403 responses from PostgREST other than
+// JWT failures, and Postgres error 42501. Such errors are
+// *RowLevelSecurityError values. A select that policies filter returns an
+// empty result rather than this error.
+var ErrRowLevelSecurity = errors.New("supabase: permission denied by row-level security")
+
+// RowLevelSecurityError is returned when a request is refused by row-level
+// security or missing grants. It matches ErrRowLevelSecurity with errors.Is
+// and wraps the *APIError.
+type RowLevelSecurityError struct {
+	// Table is the table or view, or the function for RPC calls.
+	Table string
+	// Operation is select, insert, update, upsert, delete, or execute.
+	Operation string
+	Err       *APIError
+}
+
+func (e *RowLevelSecurityError) Error() string {
+	msg := fmt.Sprintf("supabase: permission denied for %s on %s", e.Operation, e.Table)
+	switch {
+	case e.Err.StatusCode == http.StatusUnauthorized:
+		msg += " as anon; sign the user in and send their access token, or add a policy for anon"
+	case strings.Contains(e.Err.Message, "row-level security"):
+		msg += "; no row-level security policy allows it for this role and row"
+	default:
+		msg += "; the role may lack a GRANT on it or no row-level security policy allows it"
+	}
+	return msg + " (" + e.Err.Error() + ")"
+}
+
+func (e *RowLevelSecurityError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RowLevelSecurityError) Is(target error) bool {
+	return target == ErrRowLevelSecurity
+}
+
+// rowLevelSecurityError wraps err in a *RowLevelSecurityError when it is a
+// permission failure of a request to endpoint, and returns it unchanged
+// otherwise.
+func rowLevelSecurityError(method, endpoint string, err *APIError) error {
+	denied := err.Code == "42501" ||
+		(err.StatusCode == http.StatusUnauthorized || err.StatusCode == http.StatusForbidden) && !strings.HasPrefix(err.Code, "PGRST3")
+	if !denied {
+		return err
+	}
+	table, _, _ := strings.Cut(endpoint, "?")
+	operation := map[string]string{
+		"GET": "select", "HEAD": "select", "POST": "insert",
+		"PATCH": "update", "PUT": "upsert", "DELETE": "delete",
+	}[method]
+	if function, ok := strings.CutPrefix(table, "rpc/"); ok {
+		table, operation = function, "execute"
+	}
+	return &RowLevelSecurityError{Table: table, Operation: operation, Err: err}
+}
+
+// newAPIError builds the error for a non-2xx response.
+func newAPIError(statusCode int, body []byte) *APIError {
+	e := &APIError{StatusCode: statusCode, Body: body}
+	var fields map[string]any
+	if json.Unmarshal(body, &fields) != nil {
+		return e
+	}
+	str := func(keys ...string) string {
+		for _, key := range keys {
+			if s, ok := fields[key].(string); ok && s != "" {
+				return s
+			}
+		}
+		return ""
+	}
+	e.Code = str("code", "error_code")
+	e.Message = str("message", "msg", "error_description")
+	e.Details = str("details")
+	e.Hint = str("hint")
+	if oauth := str("error"); oauth != "" {
+		// OAuth-style bodies: {error, error_description} or just {error}.
+		if e.Message == "" {
+			e.Message = oauth
+		} else if e.Code == "" {
+			e.Code = oauth
+		}
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("error: %s", scrub(e.Body))
+	}
+	msg := "error: " + scrubString(e.Message)
+	if e.Code != "" {
+		msg += " (" + e.Code + ")"
+	}
+	if e.Hint != "" {
+		msg += ": " + scrubString(e.Hint)
+	}
+	return msg
+}
+
+// maxErrorBody caps the amount of an unrecognised body included in errors.
+const maxErrorBody = 512
+
+var (
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[^\s",]+`)
+)
+
+// scrub masks credentials in a response body for inclusion in an error.
+func scrub(body []byte) string {
+	s := scrubString(string(maskBody(body)))
+	if len(s) > maxErrorBody {
+		s = s[:maxErrorBody] + "..."
+	}
+	return s
+}
+
+// scrubString masks JWTs and bearer tokens in s.
+func scrubString(s string) string {
+	s = jwtPattern.ReplaceAllString(s, "***")
+	return bearerPattern.ReplaceAllString(s, "Bearer ***")
+}
@@ -0,0 +1,204 @@
+package supabase
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+// ExportFormat is the file format written by Export.
+type ExportFormat string
+
+const (
+	// ExportJSONL writes one JSON object per line.
+	ExportJSONL ExportFormat = "jsonl"
+	// ExportCSV writes a header row followed by one record per row. Strings
+	// are written as is, null as an empty cell, and objects and arrays as
+	// JSON.
+	ExportCSV ExportFormat = "csv"
+)
+
+// ExportOptions configures Export and ExportFile.
+type ExportOptions struct {
+	// Format defaults to ExportJSONL.
+	Format ExportFormat
+	// Key is a unique column the export pages by; it defaults to id.
+	Key Column
+	// PageSize is the number of rows per request, 1000 by default.
+	PageSize int
+	// Columns fixes the CSV columns and their order. By default the keys of
+	// the first page are used, sorted.
+	Columns []string
+	// Gzip compresses the output. Each page is written as its own gzip
+	// member, which gzip readers treat as one stream, so ExportFile can
+	// resume by appending members after the last recorded page.
+	Gzip bool
+}
+
+// exportState is the progress of an export, saved next to the output of
+// ExportFile so it can resume.
+type exportState struct {
+	Cursor  Cursor   `json:"cursor"`
+	Columns []string `json:"columns,omitempty"`
+	Rows    int      `json:"rows"`
+	// Offset is the size of the output after the last recorded page.
+	Offset int64 `json:"offset"`
+}
+
+// Export writes the rows of q to w, paging through the table by opts.Key,
+// and returns the number of rows written.
+func Export(ctx context.Context, q *QueryBuilder, w io.Writer, opts ExportOptions) (int, error) {
+	state := exportState{}
+	err := export(ctx, q, w, opts, &state, func() error { return nil })
+	return state.Rows, err
+}
+
+// ExportFile writes the rows of q to the file at path, like Export. Progress
+// is recorded in path + ".state" after every page; if that file exists when
+// ExportFile starts, the export resumes after the last recorded page instead
+// of starting over. The state file is removed once the export completes.
+// Anything written after the last recorded page, such as a partial page or
+// gzip member, is truncated away before resuming.
+func ExportFile(ctx context.Context, q *QueryBuilder, path string, opts ExportOptions) (int, error) {
+	statePath := path + ".state"
+	var state exportState
+	if data, err := os.ReadFile(statePath); err == nil {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return 0, fmt.Errorf("failed to read export state: %v", err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("failed to read export state: %v", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return state.Rows, fmt.Errorf("failed to open export file: %v", err)
+	}
+	if err := f.Truncate(state.Offset); err != nil {
+		f.Close()
+		return state.Rows, fmt.Errorf("failed to truncate export file: %v", err)
+	}
+	if _, err := f.Seek(state.Offset, io.SeekStart); err != nil {
+		f.Close()
+		return state.Rows, fmt.Errorf("failed to open export file: %v", err)
+	}
+	save := func() error {
+		if err := f.Sync(); err != nil {
+			return fmt.Errorf("failed to sync export file: %v", err)
+		}
+		offset, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("failed to sync export file: %v", err)
+		}
+		state.Offset = offset
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(statePath, data, 0o644); err != nil {
+			return fmt.Errorf("failed to save export state: %v", err)
+		}
+		return nil
+	}
+	if err := export(ctx, q, f, opts, &state, save); err != nil {
+		f.Close()
+		return state.Rows, err
+	}
+	if err := f.Close(); err != nil {
+		return state.Rows, fmt.Errorf("failed to close export file: %v", err)
+	}
+	if err := os.Remove(statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return state.Rows, fmt.Errorf("failed to remove export state: %v", err)
+	}
+	return state.Rows, nil
+}
+
+// export writes pages of q to w starting after state.Cursor, updating state
+// and calling save after each page.
+func export(ctx context.Context, q *QueryBuilder, w io.Writer, opts ExportOptions, state *exportState, save func() error) error {
+	if opts.Format == "" {
+		opts.Format = ExportJSONL
+	}
+	if opts.Format != ExportJSONL && opts.Format != ExportCSV {
+		return fmt.Errorf("unknown export format %q", opts.Format)
+	}
+	if opts.Key == "" {
+		opts.Key = "id"
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = defaultPageSize
+	}
+	if state.Columns == nil {
+		state.Columns = opts.Columns
+	}
+
+	pager := q.Keyset(opts.Key, opts.PageSize).After(state.Cursor)
+	var rows []json.RawMessage
+	for pager.Next(ctx, &rows) {
+		out, closeOut := w, func() error { return nil }
+		if opts.Gzip {
+			gz := gzip.NewWriter(w)
+			out, closeOut = gz, gz.Close
+		}
+		writeHeader := state.Rows == 0 && state.Cursor == ""
+		if err := writeExportPage(out, q.client.getCodec(), opts.Format, rows, state, writeHeader); err != nil {
+			return err
+		}
+		if err := closeOut(); err != nil {
+			return fmt.Errorf("failed to write export: %v", err)
+		}
+		state.Cursor = pager.Cursor()
+		state.Rows += len(rows)
+		if err := save(); err != nil {
+			return err
+		}
+	}
+	return pager.Err()
+}
+
+// writeExportPage writes one page of rows in format.
+func writeExportPage(w io.Writer, codec Codec, format ExportFormat, rows []json.RawMessage, state *exportState, writeHeader bool) error {
+	if format == ExportJSONL {
+		for _, row := range rows {
+			if _, err := fmt.Fprintf(w, "%s\n", row); err != nil {
+				return fmt.Errorf("failed to write export: %v", err)
+			}
+		}
+		return nil
+	}
+
+	records := make([]map[string]json.RawMessage, len(rows))
+	for i, row := range rows {
+		if err := codec.Unmarshal(row, &records[i]); err != nil {
+			return fmt.Errorf("failed to decode response: %v", err)
+		}
+	}
+	if state.Columns == nil {
+		for key := range records[0] {
+			state.Columns = append(state.Columns, key)
+		}
+		slices.Sort(state.Columns)
+	}
+	cw := csv.NewWriter(w)
+	if writeHeader {
+		cw.Write(state.Columns)
+	}
+	for _, record := range records {
+		cells := make([]string, len(state.Columns))
+		for i, column := range state.Columns {
+			cells[i] = jsonScalar(record[column])
+		}
+		cw.Write(cells)
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write export: %v", err)
+	}
+	return nil
+}
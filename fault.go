@@ -0,0 +1,93 @@
+package supabase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// ErrInjectedFault is the connection error returned for requests dropped by
+// fault injection.
+var ErrInjectedFault = errors.New("supabase: injected connection failure")
+
+// FaultConfig describes artificial failures for chaos testing. Rates are
+// fractions of requests between 0 and 1, so 0.05 affects 5% of requests.
+// Each fault is decided independently per request.
+type FaultConfig struct {
+	// Latency is added before a LatencyRate fraction of requests.
+	Latency     time.Duration
+	LatencyRate float64
+	// DropRate is the fraction of requests that fail with ErrInjectedFault
+	// without reaching the server, like a dropped connection.
+	DropRate float64
+	// ErrorRate is the fraction of requests answered with ErrorStatus
+	// (default 503) without reaching the server.
+	ErrorRate   float64
+	ErrorStatus int
+}
+
+// FaultTransport is an http.RoundTripper that injects the faults in Config
+// before passing requests to Base.
+type FaultTransport struct {
+	Base   http.RoundTripper
+	Config FaultConfig
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cfg := t.Config
+	if cfg.Latency > 0 && hit(cfg.LatencyRate) {
+		timer := time.NewTimer(cfg.Latency)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+	if hit(cfg.DropRate) {
+		return nil, ErrInjectedFault
+	}
+	if hit(cfg.ErrorRate) {
+		status := cfg.ErrorStatus
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		body := fmt.Sprintf(`{"message":"injected fault: %d %s"}`, status, http.StatusText(status))
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			StatusCode:    status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// hit reports whether an event with the given rate occurs.
+func hit(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
+
+// WithFaultInjection wraps the client's transport in a FaultTransport, so
+// fallback logic can be tested against simulated Supabase outages. It also
+// applies to clients supplied with WithHTTPClient, which are copied rather
+// than modified.
+func WithFaultInjection(cfg FaultConfig) Option {
+	return func(c *Client) {
+		c.faults = &cfg
+	}
+}
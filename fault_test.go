@@ -0,0 +1,64 @@
+package supabase
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFaultInjection(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		cfg   FaultConfig
+		check func(err error) bool
+	}{
+		{"drop", FaultConfig{DropRate: 1}, func(err error) bool { return errors.Is(err, ErrInjectedFault) }},
+		{"error", FaultConfig{ErrorRate: 1, ErrorStatus: 429}, func(err error) bool {
+			var apiErr *APIError
+			return errors.As(err, &apiErr) && apiErr.StatusCode == 429
+		}},
+		{"default error", FaultConfig{ErrorRate: 1}, func(err error) bool {
+			var apiErr *APIError
+			return errors.As(err, &apiErr) && apiErr.StatusCode == 503
+		}},
+	}
+	for _, tt := range tests {
+		requests = 0
+		client := NewClient(server.URL, "key", "token", WithFaultInjection(tt.cfg))
+		if _, err := client.Get("Food"); !tt.check(err) {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if requests != 0 {
+			t.Errorf("%s: Expected request not to reach the server", tt.name)
+		}
+	}
+
+	client := NewClient(server.URL, "key", "token", WithFaultInjection(FaultConfig{Latency: 20 * time.Millisecond, LatencyRate: 1}))
+	start := time.Now()
+	if _, err := client.Get("Food"); err != nil || requests != 1 {
+		t.Errorf("Expected request to pass through, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Expected injected latency, took %v", elapsed)
+	}
+}
+
+func TestFaultInjectionCustomHTTPClient(t *testing.T) {
+	custom := &http.Client{}
+	client := NewClient("https://example.supabase.co", "key", "token", WithHTTPClient(custom), WithFaultInjection(FaultConfig{DropRate: 1}))
+	if custom.Transport != nil {
+		t.Error("Expected supplied HTTP client to be left unchanged")
+	}
+	if _, ok := client.httpClient.Transport.(*FaultTransport); !ok {
+		t.Errorf("Expected FaultTransport, got %T", client.httpClient.Transport)
+	}
+}
@@ -0,0 +1,382 @@
+package supabase
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Filter is a condition on a column, built with constructors such as In and
+// applied with QueryBuilder.Where:
+//
+//	client.From("Food").Where(supabase.In("id", []int{1, 2, 3}))
+type Filter struct {
+	// Column is empty for the logical operators "or" and "and", unless
+	// they apply to an embedded resource (see ForEmbedded).
+	Column   Column
+	Operator string
+	// Value is the operand as sent after the operator, e.g. "(1,2,3)".
+	Value string
+	// Negated filters match the rows the filter would otherwise exclude.
+	Negated bool
+}
+
+// Where adds filters to the query. Filters are combined with AND.
+func (q *QueryBuilder) Where(filters ...Filter) *QueryBuilder {
+	for _, f := range filters {
+		if !f.isLogical() {
+			q.checkColumns(f.Column)
+		}
+		key, value := f.param()
+		q.query.Add(key, value)
+	}
+	return q
+}
+
+// String returns the filter in query string form, e.g. id=in.(1,2,3).
+func (f Filter) String() string {
+	key, value := f.param()
+	return key + "=" + value
+}
+
+// param returns the query parameter for the filter.
+func (f Filter) param() (key, value string) {
+	operator := f.Operator
+	if f.Negated {
+		operator = "not." + operator
+	}
+	if f.isLogical() {
+		if f.Column != "" {
+			operator = string(f.Column) + "." + operator
+		}
+		return operator, f.Value
+	}
+	return string(f.Column), operator + "." + f.Value
+}
+
+func (f Filter) isLogical() bool {
+	return f.Operator == "or" || f.Operator == "and"
+}
+
+// condition renders the filter as an item of an or/and tree, e.g.
+// rating.gte.4 or not.and(a.eq.1,b.eq.2). Scalar operands containing
+// reserved characters are quoted.
+func (f Filter) condition() string {
+	operator := f.Operator
+	if f.Negated {
+		operator = "not." + operator
+	}
+	if f.isLogical() {
+		return operator + f.Value
+	}
+	value := f.Value
+	if scalarOperators[f.Operator] {
+		value = quoteListItem(value)
+	}
+	return string(f.Column) + "." + operator + "." + value
+}
+
+// scalarOperators take a single value, which must be quoted inside or/and
+// trees when it contains reserved characters.
+var scalarOperators = map[string]bool{
+	"eq": true, "neq": true, "gt": true, "gte": true, "lt": true, "lte": true,
+	"like": true, "ilike": true, "match": true, "imatch": true, "isdistinct": true,
+}
+
+// Not negates f, rendering PostgREST's not. prefix. It can be applied to any
+// filter, including Or and And trees and filters inside them:
+//
+//	supabase.Not(supabase.Like("name", "*test*"))
+//	// name=not.like.*test*
+func Not(f Filter) Filter {
+	f.Negated = !f.Negated
+	return f
+}
+
+// Or matches rows that satisfy any of filters. Trees can be nested:
+//
+//	supabase.Or(supabase.Eq("status", "open"), supabase.And(supabase.Eq("status", "held"), supabase.Not(supabase.Eq("owner", "bot"))))
+//	// or=(status.eq.open,and(status.eq.held,owner.not.eq.bot))
+func Or(filters ...Filter) Filter {
+	return logical("or", filters)
+}
+
+// And matches rows that satisfy all of filters. At the top level filters are
+// already combined with AND; And is for nesting inside Or or negating a
+// group with Not.
+func And(filters ...Filter) Filter {
+	return logical("and", filters)
+}
+
+func logical(operator string, filters []Filter) Filter {
+	conditions := make([]string, len(filters))
+	for i, f := range filters {
+		conditions[i] = f.condition()
+	}
+	return Filter{Operator: operator, Value: "(" + strings.Join(conditions, ",") + ")"}
+}
+
+// ForEmbedded applies f to the embedded resource relation instead of the
+// queried table:
+//
+//	client.From("customers").
+//		Select("name", supabase.InnerJoin("orders", "id", "status")).
+//		Where(supabase.ForEmbedded("orders", supabase.Eq("status", "open")))
+//	// select=name,orders!inner(id,status)&orders.status=eq.open
+//
+// On its own such a filter only removes rows from the embedded array; embed
+// the relation with InnerJoin to also drop parent rows without a match.
+// Filters on embedded columns can be combined inside Or and And, e.g.
+// Or(ForEmbedded("orders", Eq("status", "open")), Eq("vip", true)), which
+// needs InnerJoin as well. Applied to an Or or And filter, ForEmbedded
+// scopes the whole tree (orders.or=(...)).
+func ForEmbedded(relation string, f Filter) Filter {
+	if f.isLogical() && f.Column == "" {
+		f.Column = Column(relation)
+	} else {
+		f.Column = Column(relation) + "." + f.Column
+	}
+	return f
+}
+
+// Eq matches rows where column equals value.
+func Eq(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "eq", Value: formatValue(value)}
+}
+
+// IsValue is an operand of Is.
+type IsValue string
+
+const (
+	Null    IsValue = "null"
+	True    IsValue = "true"
+	False   IsValue = "false"
+	Unknown IsValue = "unknown"
+)
+
+// Is matches rows where column is null, true, false, or unknown (a null
+// boolean). Equality filters cannot match null, so nullable columns need Is:
+//
+//	client.GetWhere(ctx, "Food", supabase.Is("deleted_at", supabase.Null))
+//	// deleted_at=is.null
+//	supabase.Not(supabase.Is("deleted_at", supabase.Null))
+//	// deleted_at=not.is.null
+func Is(column Column, value IsValue) Filter {
+	return Filter{Column: column, Operator: "is", Value: string(value)}
+}
+
+// Neq matches rows where column does not equal value. Rows where column is
+// null are not matched either; use Is for those.
+func Neq(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "neq", Value: formatValue(value)}
+}
+
+// Gt matches rows where column is greater than value:
+//
+//	client.From("Food").Where(supabase.Gte("rating", 4), supabase.Lt("price", 10))
+//	// rating=gte.4&price=lt.10
+func Gt(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "gt", Value: formatValue(value)}
+}
+
+// Gte matches rows where column is greater than or equal to value.
+func Gte(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "gte", Value: formatValue(value)}
+}
+
+// Lt matches rows where column is less than value.
+func Lt(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "lt", Value: formatValue(value)}
+}
+
+// Lte matches rows where column is less than or equal to value.
+func Lte(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "lte", Value: formatValue(value)}
+}
+
+// In matches rows where column equals any of values. Values are quoted as
+// needed, so commas, quotes, and parentheses inside them are matched
+// literally:
+//
+//	supabase.In("status", []string{"open", "on hold, pending"})
+//	// status=in.(open,"on hold, pending")
+func In[T any](column Column, values []T) Filter {
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = quoteListItem(formatValue(v))
+	}
+	return Filter{Column: column, Operator: "in", Value: "(" + strings.Join(items, ",") + ")"}
+}
+
+// quoteListItem quotes a value for use in a PostgREST list or logic tree
+// when it contains reserved characters, escaping quotes and backslashes.
+func quoteListItem(s string) string {
+	if s != "" && !strings.ContainsAny(s, `,()"\:. `) {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Like matches column against a case-sensitive pattern in which * matches any
+// sequence of characters. % and _ are matched literally rather than as SQL
+// wildcards. Build patterns from user input with EscapeLike so the input
+// cannot add wildcards of its own:
+//
+//	supabase.Like("name", "*"+supabase.EscapeLike(search)+"*")
+func Like(column Column, pattern string) Filter {
+	return Filter{Column: column, Operator: "like", Value: likePattern(pattern)}
+}
+
+// ILike is the case-insensitive form of Like.
+func ILike(column Column, pattern string) Filter {
+	return Filter{Column: column, Operator: "ilike", Value: likePattern(pattern)}
+}
+
+// Substring matches rows where column contains s, case-sensitively. s is
+// matched literally; wildcards in it are escaped:
+//
+//	supabase.ISubstring("food_name", "50% off")
+//	// food_name=ilike.*50\% off*
+func Substring(column Column, s string) Filter {
+	return Like(column, "*"+EscapeLike(s)+"*")
+}
+
+// ISubstring is the case-insensitive form of Substring, for search boxes.
+func ISubstring(column Column, s string) Filter {
+	return ILike(column, "*"+EscapeLike(s)+"*")
+}
+
+// Match matches column against a POSIX regular expression (~).
+func Match(column Column, regex string) Filter {
+	return Filter{Column: column, Operator: "match", Value: regex}
+}
+
+// IMatch matches column against a case-insensitive POSIX regular
+// expression (~*).
+func IMatch(column Column, regex string) Filter {
+	return Filter{Column: column, Operator: "imatch", Value: regex}
+}
+
+// EscapeLike escapes s for use in a Like or ILike pattern so every character
+// matches only itself. PostgREST turns every * into a wildcard, so a literal
+// asterisk is matched with the single-character wildcard instead; this can
+// match one other character in its place but never a longer run.
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`).Replace(s)
+}
+
+// likePattern converts a Like pattern to the form PostgREST expects,
+// escaping SQL wildcards that are meant literally.
+func likePattern(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		if ch == '\\' && i+1 < len(pattern) {
+			i++
+			ch = pattern[i]
+			if ch == '*' {
+				b.WriteByte('_')
+				continue
+			}
+		} else if ch == '*' {
+			b.WriteByte('*')
+			continue
+		}
+		switch ch {
+		case '%', '_', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// Any matches rows where operator holds for at least one of values, using
+// PostgREST's (any) modifier, which is cheaper than an or= tree:
+//
+//	supabase.Any("name", "ilike", []string{"*ramen*", "*udon*"})
+//	// name=ilike(any).{*ramen*,*udon*}
+//
+// operator is one of eq, like, ilike, match, imatch, gt, gte, lt, or lte.
+// like and ilike values are patterns as for Like.
+func Any[T any](column Column, operator string, values []T) Filter {
+	return quantified(column, operator, "any", values)
+}
+
+// All is like Any but requires operator to hold for every value.
+func All[T any](column Column, operator string, values []T) Filter {
+	return quantified(column, operator, "all", values)
+}
+
+func quantified[T any](column Column, operator, quantifier string, values []T) Filter {
+	items := make([]string, len(values))
+	for i, v := range values {
+		s := formatValue(v)
+		if operator == "like" || operator == "ilike" {
+			s = likePattern(s)
+		}
+		items[i] = quoteArrayItem(s)
+	}
+	return Filter{Column: column, Operator: operator + "(" + quantifier + ")", Value: "{" + strings.Join(items, ",") + "}"}
+}
+
+// quoteArrayItem quotes a value for a Postgres array literal when needed.
+func quoteArrayItem(s string) string {
+	if s != "" && !strings.ContainsAny(s, `,{}"\ `) && !strings.EqualFold(s, "null") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Contains matches rows where the array, range, or jsonb column contains
+// value (@>). Slices are sent as Postgres arrays, maps and structs as JSON,
+// and strings as given, e.g. a range literal:
+//
+//	supabase.Contains("tags", []string{"vegan", "spicy"})
+//	// tags=cs.{vegan,spicy}
+//	supabase.Contains("address", map[string]any{"city": "Tokyo"})
+//	// address=cs.{"city":"Tokyo"}
+func Contains(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "cs", Value: containmentValue(value)}
+}
+
+// ContainedBy matches rows where the array, range, or jsonb column is
+// contained in value (<@). value is sent as for Contains.
+func ContainedBy(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "cd", Value: containmentValue(value)}
+}
+
+// Overlaps matches rows where the array or range column has an element in
+// common with value (&&). value is sent as for Contains.
+func Overlaps(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "ov", Value: containmentValue(value)}
+}
+
+// containmentValue renders the operand of Contains, ContainedBy, and
+// Overlaps.
+func containmentValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = quoteArrayItem(formatValue(v.Index(i).Interface()))
+		}
+		return "{" + strings.Join(items, ",") + "}"
+	case reflect.Map, reflect.Struct, reflect.Pointer:
+		if _, ok := value.(fmt.Stringer); ok {
+			break
+		}
+		if data, err := json.Marshal(value); err == nil {
+			return string(data)
+		}
+	}
+	return formatValue(value)
+}
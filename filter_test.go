@@ -0,0 +1,222 @@
+package supabase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestIn(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{In("id", []int{1, 2, 3}), "id=in.(1,2,3)"},
+		{In("status", []string{"open", "closed"}), "status=in.(open,closed)"},
+		{In("name", []string{"a,b", `say "hi"`, `back\slash`, "(x)", ""}), `name=in.("a,b","say \"hi\"","back\\slash","(x)","")`},
+		{In("score", []float64{1.5}), `score=in.("1.5")`},
+		{In("id", []int{}), "id=in.()"},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.String(); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+	}
+}
+
+func TestWhereIn(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token")
+	q := client.From("Food").Where(In("food_name", []string{"Ramen", "Mac, cheese"}), In("rating", []int{4, 5}))
+	if _, err := q.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if got.Get("food_name") != `in.(Ramen,"Mac, cheese")` || got.Get("rating") != "in.(4,5)" {
+		t.Errorf("Unexpected query %v", got)
+	}
+}
+
+func TestLike(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{Like("name", "*ramen*"), "name=like.*ramen*"},
+		{ILike("name", "50%_off*"), `name=ilike.50\%\_off*`},
+		{ILike("name", "*"+EscapeLike(`a*b\c`)+"*"), `name=ilike.*a_b\\c*`},
+		{Match("code", "^[A-Z]{3}$"), "code=match.^[A-Z]{3}$"},
+		{IMatch("name", "^ra"), "name=imatch.^ra"},
+		{Substring("name", "ramen"), "name=like.*ramen*"},
+		{ISubstring("name", "50% off_*"), `name=ilike.*50\% off\__*`},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.String(); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+	}
+}
+
+func TestAnyAll(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{Any("name", "ilike", []string{"*ramen*", "*udon*"}), "name=ilike(any).{*ramen*,*udon*}"},
+		{Any("id", "eq", []int{1, 2}), "id=eq(any).{1,2}"},
+		{All("rating", "gt", []int{3}), "rating=gt(all).{3}"},
+		{Any("name", "eq", []string{"a,b", `q"t`, "null", "100%"}), `name=eq(any).{"a,b","q\"t","null",100%}`},
+		{All("name", "like", []string{"*50%*"}), `name=like(all).{"*50\\%*"}`},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.String(); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+	}
+	if err := validateQuery("Food", map[string][]string{"name": {Any("name", "ilike", []string{"*a*"}).Operator + ".{*a*}"}}); err != nil {
+		t.Errorf("Expected modifier to pass validation, got %v", err)
+	}
+}
+
+func TestNotAndLogic(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{Not(Like("name", "*test*")), "name=not.like.*test*"},
+		{Not(In("id", []int{1, 2})), "id=not.in.(1,2)"},
+		{Not(Not(Eq("id", 1))), "id=eq.1"},
+		{
+			Or(Eq("status", "open"), And(Eq("status", "held"), Not(Eq("owner", "bot")))),
+			"or=(status.eq.open,and(status.eq.held,owner.not.eq.bot))",
+		},
+		{Not(Or(Eq("a", 1), Eq("b", 2))), "not.or=(a.eq.1,b.eq.2)"},
+		{Or(Eq("name", "Mac, cheese"), In("id", []int{1, 2}), Not(And(Eq("x", 1), Eq("y", 2)))), `or=(name.eq."Mac, cheese",id.in.(1,2),not.and(x.eq.1,y.eq.2))`},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.String(); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+		key, value := tt.filter.param()
+		if err := validateParam(key, value); err != nil {
+			t.Errorf("Expected %s to pass validation, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestForEmbedded(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{ForEmbedded("orders", Eq("status", "open")), "orders.status=eq.open"},
+		{ForEmbedded("orders", Not(In("status", []string{"void"}))), "orders.status=not.in.(void)"},
+		{ForEmbedded("orders", Or(Eq("status", "open"), Eq("total", 100))), "orders.or=(status.eq.open,total.eq.100)"},
+		{ForEmbedded("customers", ForEmbedded("orders", Not(And(Eq("a", 1), Eq("b", 2))))), "customers.orders.not.and=(a.eq.1,b.eq.2)"},
+		{Or(ForEmbedded("orders", Eq("status", "open")), ForEmbedded("orders", Eq("total", 100))), "or=(orders.status.eq.open,orders.total.eq.100)"},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.String(); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+		key, value := tt.filter.param()
+		if err := validateParam(key, value); err != nil {
+			t.Errorf("Expected %s to pass validation, got %v", tt.want, err)
+		}
+	}
+
+	q := NewClient("https://example.supabase.co", "key", "token").From("customers").
+		Select("name", InnerJoin("orders", "id", "status")).
+		Where(ForEmbedded("orders", Eq("status", "open")))
+	if got, want := q.Query().Get("select"), "name,orders!inner(id,status)"; got != want {
+		t.Errorf("Expected select %s, got %s", want, got)
+	}
+	if _, err := q.Prepare(); err != nil {
+		t.Errorf("Expected query to pass validation, got %v", err)
+	}
+
+	q = NewClient("https://example.supabase.co", "key", "token").From("Food").
+		Select("*", InnerJoin("reviews")).
+		Where(ForEmbedded("reviews", Gte("rating", 4)))
+	if got, want := q.String(), "https://example.supabase.co/rest/v1/Food?reviews.rating=gte.4&select=%2A%2Creviews%21inner%28%2A%29"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestComparison(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{Neq("status", "sold out"), "status=neq.sold out"},
+		{Gt("rating", 4), "rating=gt.4"},
+		{Gte("price", 9.5), "price=gte.9.5"},
+		{Lt("created_at", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)), "created_at=lt.2024-01-02T03:04:05Z"},
+		{Lte("rating", 2), "rating=lte.2"},
+		{Not(Gt("rating", 4)), "rating=not.gt.4"},
+		{Is("deleted_at", Null), "deleted_at=is.null"},
+		{Not(Is("deleted_at", Null)), "deleted_at=not.is.null"},
+		{Is("active", True), "active=is.true"},
+		{Is("active", Unknown), "active=is.unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.String(); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+	}
+	if got := Or(Lt("price", 5), Neq("name", "a,b"), Is("price", Null)).String(); got != `or=(price.lt.5,name.neq."a,b",price.is.null)` {
+		t.Errorf("Expected comparisons inside or tree, got %s", got)
+	}
+}
+
+func TestGetWhere(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token")
+	if _, err := client.GetWhere(context.Background(), "Food", Gte("rating", 4), Lt("rating", 5), Eq("id", 1)); err != nil {
+		t.Fatalf("GetWhere returned error: %v", err)
+	}
+	if ratings := got["rating"]; len(ratings) != 2 || ratings[0] != "gte.4" || ratings[1] != "lt.5" || got.Get("id") != "eq.1" {
+		t.Errorf("Unexpected query %v", got)
+	}
+}
+
+func TestContainment(t *testing.T) {
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{Contains("tags", []string{"vegan", "spicy"}), "tags=cs.{vegan,spicy}"},
+		{Contains("tags", []string{"a b", "null"}), `tags=cs.{"a b","null"}`},
+		{ContainedBy("ids", []int{1, 2, 3}), "ids=cd.{1,2,3}"},
+		{Overlaps("tags", [2]string{"x", "y"}), "tags=ov.{x,y}"},
+		{Contains("address", map[string]any{"city": "Tokyo"}), `address=cs.{"city":"Tokyo"}`},
+		{Contains("meta", struct {
+			Done bool `json:"done"`
+		}{true}), `meta=cs.{"done":true}`},
+		{Overlaps("during", "[2024-01-01,2024-02-01)"), "during=ov.[2024-01-01,2024-02-01)"},
+		{Not(Contains("tags", []string{"x"})), "tags=not.cs.{x}"},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.String(); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+		key, value := tt.filter.param()
+		if err := validateParam(key, value); err != nil {
+			t.Errorf("Expected %s to pass validation, got %v", tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// ErrRowLimit is returned by GetAll when the result has more rows than the
+// cap it was given.
+var ErrRowLimit = errors.New("supabase: result exceeds row limit")
+
+// defaultPageSize matches the default db-max-rows of Supabase projects.
+const defaultPageSize = 1000
+
+// GetAll runs q page by page and returns every matching row, for moderate
+// exports where KeysetPager is more than needed. The page size is the
+// query's Limit, or 1000 without one; when the server caps pages lower (its
+// db-max-rows setting), GetAll adapts to the smaller pages. Order the query
+// by a unique column so rows do not shift between pages.
+//
+// maxRows caps the result: if more rows match, GetAll returns the first
+// maxRows rows and ErrRowLimit. A maxRows of zero or less disables the cap.
+func GetAll[T any](ctx context.Context, q *QueryBuilder, maxRows int) ([]T, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	pageSize := defaultPageSize
+	if limit, err := strconv.Atoi(q.query.Get("limit")); err == nil && limit > 0 {
+		pageSize = limit
+	}
+	offset := 0
+	if n, err := strconv.Atoi(q.query.Get("offset")); err == nil && n > 0 {
+		offset = n
+	}
+
+	var all []T
+	capped := false
+	for {
+		if err := ctx.Err(); err != nil {
+			return all, err
+		}
+		query := cloneValues(q.query)
+		query.Set("limit", strconv.Itoa(pageSize))
+		query.Set("offset", strconv.Itoa(offset))
+		resp, err := q.client.send(ctx, http.MethodGet, q.table, query, nil)
+		if err != nil {
+			return all, err
+		}
+		var page []T
+		if err := q.client.getCodec().Unmarshal(resp.Body, &page); err != nil {
+			return all, fmt.Errorf("failed to decode response: %v", err)
+		}
+		if maxRows > 0 && len(all)+len(page) > maxRows {
+			return append(all, page[:maxRows-len(all)]...), ErrRowLimit
+		}
+		all = append(all, page...)
+		offset += len(page)
+
+		info, ok := resp.PageInfo()
+		switch {
+		case len(page) == 0:
+			return all, nil
+		case ok && info.Total >= 0:
+			if offset >= info.Total {
+				return all, nil
+			}
+		case len(page) < pageSize:
+			if capped {
+				return all, nil
+			}
+			// Either the last page or the server caps pages below
+			// pageSize; continue with the smaller size to find out.
+			pageSize, capped = len(page), true
+		}
+	}
+}
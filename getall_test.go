@@ -0,0 +1,74 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// pagingServer serves rows 0..total-1, capping pages at maxRows like
+// PostgREST's db-max-rows.
+func pagingServer(total, maxRows int, requests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		limit = min(limit, maxRows)
+		var rows []map[string]int
+		for i := offset; i < min(offset+limit, total); i++ {
+			rows = append(rows, map[string]int{"id": i})
+		}
+		if rows == nil {
+			rows = []map[string]int{}
+		}
+		json.NewEncoder(w).Encode(rows)
+	}))
+}
+
+type idRow struct {
+	ID int `json:"id"`
+}
+
+func TestGetAll(t *testing.T) {
+	var requests int
+	server := pagingServer(1250, 500, &requests)
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token")
+
+	rows, err := GetAll[idRow](context.Background(), client.From("items").Order("id", true), 0)
+	if err != nil {
+		t.Fatalf("GetAll returned error: %v", err)
+	}
+	if len(rows) != 1250 || rows[1249].ID != 1249 {
+		t.Fatalf("Expected 1250 rows in order, got %d", len(rows))
+	}
+	// 500 (capped), 500, 250 (last).
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+
+	requests = 0
+	rows, err = GetAll[idRow](context.Background(), client.From("items").Limit(100), 150)
+	if !errors.Is(err, ErrRowLimit) || len(rows) != 150 {
+		t.Errorf("Expected 150 rows and ErrRowLimit, got %d, %v", len(rows), err)
+	}
+}
+
+func TestGetAllCancelled(t *testing.T) {
+	var requests int
+	server := pagingServer(10, 10, &requests)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GetAll[idRow](ctx, NewClient(server.URL, "key", "token").From("items"), 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+}
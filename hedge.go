@@ -0,0 +1,71 @@
+package supabase
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WithHedging sends a second, identical GET or HEAD request when the first has
+// not completed after delay, and uses whichever succeeds first. The slower
+// request is cancelled. This trades a small amount of extra load for lower
+// tail latency; pick a delay around the p95 of normal reads.
+func WithHedging(delay time.Duration) Option {
+	return func(c *Client) {
+		c.hedgeDelay = delay
+	}
+}
+
+// executeAttempt performs a single logical attempt of a request, hedging
+// reads when enabled.
+func (c *Client) executeAttempt(ctx context.Context, method, endpoint string, query url.Values, header http.Header, body []byte) (*Response, error) {
+	if c.hedgeDelay <= 0 || (method != http.MethodGet && method != http.MethodHead) {
+		return c.execute(ctx, method, endpoint, query, header, body)
+	}
+	return c.executeHedged(ctx, method, endpoint, query, header, body)
+}
+
+// executeHedged races a primary request against a delayed hedge request.
+func (c *Client) executeHedged(ctx context.Context, method, endpoint string, query url.Values, header http.Header, body []byte) (*Response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		resp *Response
+		err  error
+	}
+	results := make(chan result, 2)
+	run := func() {
+		resp, err := c.execute(ctx, method, endpoint, query, header, body)
+		results <- result{resp, err}
+	}
+
+	go run()
+	timer := time.NewTimer(c.hedgeDelay)
+	defer timer.Stop()
+	hedge := timer.C
+
+	launched, received := 1, 0
+	var lastErr error
+	for received < launched {
+		select {
+		case <-hedge:
+			hedge = nil
+			launched++
+			go run()
+		case r := <-results:
+			received++
+			if r.err == nil {
+				return r.resp, nil
+			}
+			lastErr = r.err
+			if hedge != nil {
+				// The primary failed before the hedge was sent; leave
+				// retrying to the retry policy.
+				return nil, lastErr
+			}
+		}
+	}
+	return nil, lastErr
+}
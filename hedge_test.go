@@ -0,0 +1,59 @@
+package supabase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithHedging(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			// The primary request stalls until it is cancelled.
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		w.Write([]byte(`["hedged"]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "your_api_key", "your_token", WithHedging(20*time.Millisecond))
+	start := time.Now()
+	body, err := client.Get("Food")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if string(body) != `["hedged"]` {
+		t.Errorf("Expected the hedged response, got %s", body)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected hedged request to avoid the stalled primary, took %v", elapsed)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected 2 requests, got %d", n)
+	}
+}
+
+func TestWithHedgingSkipsWrites(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(30 * time.Millisecond)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "your_api_key", "your_token", WithHedging(time.Millisecond))
+	if _, err := client.Post("Food", []byte(`{}`)); err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected writes not to be hedged, got %d requests", n)
+	}
+}
@@ -0,0 +1,97 @@
+package supabase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// idempotencyKeyHeader carries the key that lets the database recognise a
+// retried write it has already applied. PostgREST exposes it to SQL as
+// current_setting('request.headers')::json->>'idempotency-key'.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// NewIdempotencyKey returns a random 128-bit key encoded as hex.
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithIdempotencyKey returns a copy of the client that sends key in the
+// Idempotency-Key header. Use a fresh copy per logical write:
+//
+//	client.WithIdempotencyKey(supabase.NewIdempotencyKey()).Post("Food", data)
+//
+// POST and PATCH requests are only retried by WithRetryPolicy when they carry
+// a key, since the database needs it to discard duplicates; see the README for
+// the companion server-side pattern.
+func (c *Client) WithIdempotencyKey(key string) *Client {
+	return c.withHeader(idempotencyKeyHeader, key)
+}
+
+// WithRetryPolicy retries requests that fail transiently. GET, HEAD, PUT, and
+// DELETE are retried freely; POST and PATCH are only retried when they carry
+// an idempotency key.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+// WithMethodRetryPolicy overrides the WithRetryPolicy policy for requests
+// with the given HTTP methods, e.g. more attempts for reads:
+//
+//	supabase.WithRetryPolicy(supabase.DefaultRetryPolicy),
+//	supabase.WithMethodRetryPolicy(supabase.RetryPolicy{MaxAttempts: 5, MinBackoff: 50 * time.Millisecond, MaxBackoff: time.Second}, http.MethodGet, http.MethodHead)
+//
+// A MaxAttempts of 1 disables retries for the methods. POST and PATCH are
+// still only retried when they carry an idempotency key.
+func WithMethodRetryPolicy(policy RetryPolicy, methods ...string) Option {
+	return func(c *Client) {
+		if c.methodRetryPolicies == nil {
+			c.methodRetryPolicies = map[string]*RetryPolicy{}
+		}
+		for _, method := range methods {
+			c.methodRetryPolicies[strings.ToUpper(method)] = &policy
+		}
+	}
+}
+
+// retryPolicyFor returns the retry policy for method, or nil if requests with
+// it are not retried.
+func (c *Client) retryPolicyFor(method string) *RetryPolicy {
+	if policy, ok := c.methodRetryPolicies[method]; ok {
+		return policy
+	}
+	return c.retryPolicy
+}
+
+// canRetry reports whether a request may be retried under the retry policy.
+func (c *Client) canRetry(method string, header http.Header) bool {
+	if c.retryPolicyFor(method) == nil {
+		return false
+	}
+	switch method {
+	case http.MethodPost, http.MethodPatch:
+		return header.Get(idempotencyKeyHeader) != "" || c.header.Get(idempotencyKeyHeader) != ""
+	}
+	return true
+}
+
+// executeWithRetry performs a request, retrying it when the policy allows.
+func (c *Client) executeWithRetry(ctx context.Context, method, endpoint string, query url.Values, header http.Header, body []byte) (*Response, error) {
+	if !c.canRetry(method, header) {
+		return c.executeAttempt(ctx, method, endpoint, query, header, body)
+	}
+	var resp *Response
+	err := c.retryPolicyFor(method).do(ctx, func() error {
+		var err error
+		resp, err = c.executeAttempt(ctx, method, endpoint, query, header, body)
+		return err
+	})
+	return resp, err
+}
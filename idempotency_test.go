@@ -0,0 +1,90 @@
+package supabase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyIdempotency(t *testing.T) {
+	var attempts int
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		keys = append(keys, r.Header.Get(idempotencyKeyHeader))
+		if attempts%2 == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "your_api_key", "your_token", WithRetryPolicy(RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond}))
+
+	if _, err := client.Get("Food"); err != nil || attempts != 2 {
+		t.Errorf("Expected GET to succeed on retry, got %d attempts (%v)", attempts, err)
+	}
+
+	attempts = 0
+	if _, err := client.Post("Food", []byte(`{}`)); err == nil || attempts != 1 {
+		t.Errorf("Expected POST without idempotency key to fail without retry, got %d attempts (%v)", attempts, err)
+	}
+
+	attempts, keys = 0, nil
+	keyed := client.WithIdempotencyKey("key-1")
+	if _, err := keyed.Post("Food", []byte(`{}`)); err != nil || attempts != 2 {
+		t.Errorf("Expected keyed POST to succeed on retry, got %d attempts (%v)", attempts, err)
+	}
+	if keys[0] != "key-1" || keys[1] != "key-1" {
+		t.Errorf("Expected both attempts to carry the key, got %v", keys)
+	}
+
+	attempts, keys = 0, nil
+	client.Get("Food")
+	if keys[0] != "" {
+		t.Errorf("Expected WithIdempotencyKey not to modify the original client, got %q", keys[0])
+	}
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	a, b := NewIdempotencyKey(), NewIdempotencyKey()
+	if len(a) != 32 || a == b {
+		t.Errorf("Expected distinct 32-character keys, got %q and %q", a, b)
+	}
+}
+
+func TestMethodRetryPolicy(t *testing.T) {
+	attempts := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts[r.Method]++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token",
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2}),
+		WithMethodRetryPolicy(RetryPolicy{MaxAttempts: 4}, "get"),
+		WithMethodRetryPolicy(RetryPolicy{MaxAttempts: 1}, http.MethodDelete),
+		WithMethodRetryPolicy(RetryPolicy{MaxAttempts: 3}, http.MethodPost))
+
+	client.Get("Food")
+	client.Delete("Food", "id", "1")
+	client.Put("Food", "id", "1", []byte(`{"id":1}`))
+	client.Post("Food", []byte(`{}`))
+	client.WithIdempotencyKey("k").Patch("Food", nil, []byte(`{}`))
+
+	want := map[string]int{
+		http.MethodGet:    4,
+		http.MethodDelete: 1,
+		http.MethodPut:    2,
+		http.MethodPost:   1, // no idempotency key
+		http.MethodPatch:  2,
+	}
+	for method, n := range want {
+		if attempts[method] != n {
+			t.Errorf("Expected %d %s attempts, got %d", n, method, attempts[method])
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package supabase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IdentifierError is returned by QueryBuilder methods when a table or column
+// name is obviously invalid, before the request is sent. Like QueryError, it
+// is not reported for clients created with WithoutQueryValidation.
+type IdentifierError struct {
+	// Kind is "table" or "column".
+	Kind   string
+	Name   string
+	Reason string
+}
+
+func (e *IdentifierError) Error() string {
+	return fmt.Sprintf("supabase: invalid %s name %q: %s", e.Kind, e.Name, e.Reason)
+}
+
+// QuoteIdent quotes name as a PostgREST identifier, for columns whose names
+// contain characters with a meaning in query syntax such as '.', ',', ':',
+// or parentheses:
+//
+//	client.From("events").Select(supabase.QuoteIdent("event.type"))
+//	// select="event.type"
+func QuoteIdent(name string) Column {
+	return Column(`"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name) + `"`)
+}
+
+// unquoteTable returns a table name without surrounding double quotes. Table
+// names are sent in the URL path, where PostgREST takes them literally, so
+// quoting is never needed there.
+func unquoteTable(name string) string {
+	if len(name) >= 2 && strings.HasPrefix(name, `"`) && strings.HasSuffix(name, `"`) {
+		return strings.ReplaceAll(name[1:len(name)-1], `""`, `"`)
+	}
+	return name
+}
+
+// validateTable checks a table or view name. Spaces and capitals are allowed
+// and escaped in the URL.
+func validateTable(name string) error {
+	reason := ""
+	switch {
+	case name == "":
+		reason = "must not be empty"
+	case strings.TrimSpace(name) != name:
+		reason = "has leading or trailing whitespace"
+	case strings.ContainsAny(name, "/?#"):
+		reason = "must not contain '/', '?', or '#'"
+	case hasControl(name):
+		reason = "contains a control character"
+	}
+	if reason != "" {
+		return &IdentifierError{Kind: "table", Name: name, Reason: reason}
+	}
+	return nil
+}
+
+// validateColumn checks a column reference as used in select, order, and
+// filters. Aliases, casts, JSON paths, and embedded resources are allowed, so
+// only names that cannot be valid in any of these forms are rejected.
+func validateColumn(column Column) error {
+	name := string(column)
+	reason := ""
+	switch {
+	case name == "":
+		reason = "must not be empty"
+	case strings.TrimSpace(name) != name:
+		reason = "has leading or trailing whitespace; quote it with QuoteIdent"
+	case hasControl(name):
+		reason = "contains a control character"
+	default:
+		reason = checkBalanced(name)
+	}
+	if reason != "" {
+		return &IdentifierError{Kind: "column", Name: name, Reason: reason}
+	}
+	return nil
+}
+
+// checkBalanced reports unterminated quotes and unbalanced parentheses.
+func checkBalanced(name string) string {
+	depth, quoted := 0, false
+	for i := 0; i < len(name); i++ {
+		switch ch := name[i]; {
+		case ch == '\\' && quoted:
+			i++
+		case ch == '"':
+			quoted = !quoted
+		case quoted:
+		case ch == '(':
+			depth++
+		case ch == ')':
+			if depth--; depth < 0 {
+				return "unbalanced parentheses"
+			}
+		}
+	}
+	switch {
+	case quoted:
+		return "unterminated quoted identifier"
+	case depth != 0:
+		return "unbalanced parentheses"
+	}
+	return ""
+}
+
+func hasControl(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return r < 0x20 || r == 0x7f }) >= 0
+}
@@ -0,0 +1,56 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestIdentifierValidation(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "key", "token")
+
+	tests := []struct {
+		q    *QueryBuilder
+		kind string
+	}{
+		{client.From(""), "table"},
+		{client.From("Food "), "table"},
+		{client.From("a/b"), "table"},
+		{client.From("Food").Select("id", ""), "column"},
+		{client.From("Food").Eq("na\nme", 1), "column"},
+		{client.From("Food").Order(`"rating`, true), "column"},
+		{client.From("Food").Where(In("tags)", []int{1})), "column"},
+	}
+	for _, tt := range tests {
+		_, err := tt.q.Execute(context.Background())
+		var idErr *IdentifierError
+		if !errors.As(err, &idErr) || idErr.Kind != tt.kind {
+			t.Errorf("Expected %s IdentifierError for %s, got %v", tt.kind, tt.q.table, err)
+		}
+	}
+
+	valid := client.From(`"Food Items"`).
+		Select("id", "name:food_name", "price::text", "author(name)", JSONText("data", "a b"), QuoteIdent("event.type")).
+		Order(EmbeddedColumn("author", "name"), true)
+	u, err := valid.BuildURL()
+	if err != nil {
+		t.Fatalf("Expected valid identifiers, got %v", err)
+	}
+	if want := "/rest/v1/Food%20Items"; u.EscapedPath() != want {
+		t.Errorf("Expected path %s, got %s", want, u.EscapedPath())
+	}
+	if got, want := valid.Query().Get("select"), `id,name:food_name,price::text,author(name),data->>"a b","event.type"`; got != want {
+		t.Errorf("Expected select %s, got %s", want, got)
+	}
+
+	unchecked := NewClient("https://example.supabase.co", "key", "token", WithoutQueryValidation())
+	if _, err := unchecked.From("Food").Select("").BuildURL(); err != nil {
+		t.Errorf("Expected WithoutQueryValidation to skip identifier checks, got %v", err)
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	if got, want := QuoteIdent(`a"b`), Column(`"a\"b"`); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
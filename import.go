@@ -0,0 +1,277 @@
+package supabase
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ImportFormat is the file format read by Import.
+type ImportFormat string
+
+const (
+	// ImportJSON reads a JSON array of objects or JSON Lines, one object per
+	// line.
+	ImportJSON ImportFormat = "json"
+	// ImportCSV reads a header row followed by one record per row. Every
+	// cell is sent as a string, which Postgres casts to the column type, and
+	// empty cells are sent as null, matching the output of Export.
+	ImportCSV ImportFormat = "csv"
+)
+
+// ImportOptions configures Import.
+type ImportOptions struct {
+	// Format defaults to ImportJSON.
+	Format ImportFormat
+	// ChunkSize is the number of rows per request, 500 by default.
+	ChunkSize int
+	// Columns maps fields of the file to columns of the table. Fields mapped
+	// to "" are dropped; fields not in the map keep their name. A row with no
+	// fields left fails the import.
+	Columns map[string]Column
+	// OnConflict and IgnoreDuplicates are passed to Upsert for each chunk.
+	OnConflict       []Column
+	IgnoreDuplicates bool
+	// DryRun reads, maps, and checks every row without writing anything.
+	DryRun bool
+}
+
+// ImportResult reports what Import wrote, or would have written in a dry
+// run.
+type ImportResult struct {
+	Rows   int
+	Chunks int
+}
+
+// Import loads the rows read from r into table with chunked upserts, for
+// seeding environments and moving data between projects:
+//
+//	f, _ := os.Open("food.csv")
+//	result, err := client.Import(ctx, "Food", f, supabase.ImportOptions{
+//		Format:  supabase.ImportCSV,
+//		Columns: map[string]supabase.Column{"name": "food_name", "notes": ""},
+//	})
+//
+// Chunks are written in order and each commits on its own, so when a chunk
+// fails the rows of earlier chunks, counted in the result, stay written.
+// Rows may have different fields. Each row writes only its own fields: a row
+// merged into an existing one leaves the other columns unchanged, and a new
+// row gets their defaults. Rows of a chunk with different fields are sent in
+// separate requests, one per set of fields.
+func (c *Client) Import(ctx context.Context, table string, r io.Reader, opts ImportOptions) (ImportResult, error) {
+	var result ImportResult
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = 500
+	}
+	if err := validateTable(unquoteTable(table)); err != nil && !c.skipQueryValidation {
+		return result, err
+	}
+	next, err := importReader(r, opts.Format, c.getCodec())
+	if err != nil {
+		return result, err
+	}
+
+	chunk := make([]map[string]json.RawMessage, 0, opts.ChunkSize)
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		if !opts.DryRun {
+			if err := c.importChunk(ctx, table, chunk, opts); err != nil {
+				return fmt.Errorf("failed to import rows %d-%d: %w", result.Rows+1, result.Rows+len(chunk), err)
+			}
+		}
+		result.Rows += len(chunk)
+		result.Chunks++
+		chunk = chunk[:0]
+		return nil
+	}
+	for n := 1; ; n++ {
+		fields, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read row %d: %v", n, err)
+		}
+		row, err := mapImportRow(fields, opts.Columns)
+		if err != nil {
+			return result, fmt.Errorf("row %d: %w", n, err)
+		}
+		chunk = append(chunk, row)
+		if len(chunk) == opts.ChunkSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, flush()
+}
+
+// ImportFile imports the file at path like Import. Files ending in .gz are
+// decompressed, and the format defaults to ImportCSV for .csv files.
+func (c *Client) ImportFile(ctx context.Context, table, path string, opts ImportOptions) (ImportResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("failed to open import file: %v", err)
+	}
+	defer f.Close()
+	var r io.Reader = f
+	name := path
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return ImportResult{}, fmt.Errorf("failed to open import file: %v", err)
+		}
+		defer gz.Close()
+		r, name = gz, strings.TrimSuffix(name, ".gz")
+	}
+	if opts.Format == "" && strings.HasSuffix(name, ".csv") {
+		opts.Format = ImportCSV
+	}
+	return c.Import(ctx, table, r, opts)
+}
+
+// importChunk upserts one chunk. Rows are grouped by the fields they have and
+// each group is written with exactly those columns, so no row sets a column
+// it leaves out.
+func (c *Client) importChunk(ctx context.Context, table string, chunk []map[string]json.RawMessage, opts ImportOptions) error {
+	var groups [][]map[string]json.RawMessage
+	var groupColumns [][]Column
+	index := map[string]int{}
+	for _, row := range chunk {
+		columns := make([]Column, 0, len(row))
+		for name := range row {
+			columns = append(columns, Column(name))
+		}
+		slices.Sort(columns)
+		key := joinColumns(columns)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+			groupColumns = append(groupColumns, columns)
+		}
+		groups[i] = append(groups[i], row)
+	}
+	for i, group := range groups {
+		body, err := c.getCodec().Marshal(group)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %v", err)
+		}
+		if _, err := c.Upsert(ctx, table, body, UpsertOptions{
+			OnConflict:       opts.OnConflict,
+			IgnoreDuplicates: opts.IgnoreDuplicates,
+			Columns:          groupColumns[i],
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapImportRow renames the fields of a row as set by columns and checks the
+// resulting column names. A row left without fields is an error rather than
+// a row of defaults.
+func mapImportRow(fields map[string]json.RawMessage, columns map[string]Column) (map[string]json.RawMessage, error) {
+	row := make(map[string]json.RawMessage, len(fields))
+	for name, value := range fields {
+		column := Column(name)
+		if mapped, ok := columns[name]; ok {
+			if mapped == "" {
+				continue
+			}
+			column = mapped
+		}
+		if err := validateColumn(column); err != nil {
+			return nil, err
+		}
+		if _, dup := row[string(column)]; dup {
+			return nil, fmt.Errorf("more than one field maps to column %q", column)
+		}
+		row[string(column)] = value
+	}
+	if len(row) == 0 {
+		return nil, errors.New("no fields left to write")
+	}
+	return row, nil
+}
+
+// importReader returns a function that reads the next row of r, returning
+// io.EOF after the last one.
+func importReader(r io.Reader, format ImportFormat, codec Codec) (func() (map[string]json.RawMessage, error), error) {
+	switch format {
+	case ImportCSV:
+		return csvRows(r, codec)
+	case ImportJSON, "":
+		return jsonRows(r)
+	}
+	return nil, fmt.Errorf("unknown import format %q", format)
+}
+
+func csvRows(r io.Reader, codec Codec) (func() (map[string]json.RawMessage, error), error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %v", err)
+	}
+	return func() (map[string]json.RawMessage, error) {
+		record, err := cr.Read()
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]json.RawMessage, len(header))
+		for i, name := range header {
+			row[name] = json.RawMessage("null")
+			if record[i] != "" {
+				row[name], _ = codec.Marshal(record[i])
+			}
+		}
+		return row, nil
+	}, nil
+}
+
+func jsonRows(r io.Reader) (func() (map[string]json.RawMessage, error), error) {
+	br := bufio.NewReader(r)
+	dec := json.NewDecoder(br)
+	array := false
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			break
+		}
+		if b[0] == ' ' || b[0] == '\t' || b[0] == '\r' || b[0] == '\n' {
+			br.ReadByte()
+			continue
+		}
+		array = b[0] == '['
+		break
+	}
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+	return func() (map[string]json.RawMessage, error) {
+		if array && !dec.More() {
+			return nil, io.EOF
+		}
+		var row map[string]json.RawMessage
+		if err := dec.Decode(&row); err != nil {
+			return nil, err
+		}
+		if row == nil {
+			return nil, errors.New("row is not a JSON object")
+		}
+		return row, nil
+	}, nil
+}
@@ -0,0 +1,176 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	var queries, bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		queries = append(queries, r.URL.RawQuery)
+		bodies = append(bodies, string(body))
+		if got := r.Header.Get("Prefer"); got != "resolution=merge-duplicates" {
+			t.Errorf("Expected merge-duplicates preference, got %q", got)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token")
+
+	csvFile := "name,rating,notes\nRamen,5,x\nUdon,,y\nSoba,4,z\n"
+	opts := ImportOptions{
+		Format:     ImportCSV,
+		ChunkSize:  2,
+		Columns:    map[string]Column{"name": "food_name", "notes": ""},
+		OnConflict: []Column{"food_name"},
+	}
+	result, err := client.Import(context.Background(), "Food", strings.NewReader(csvFile), opts)
+	if err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	if result != (ImportResult{Rows: 3, Chunks: 2}) {
+		t.Errorf("Expected 3 rows in 2 chunks, got %+v", result)
+	}
+	wantBodies := []string{
+		`[{"food_name":"Ramen","rating":"5"},{"food_name":"Udon","rating":null}]`,
+		`[{"food_name":"Soba","rating":"4"}]`,
+	}
+	if len(bodies) != 2 || bodies[0] != wantBodies[0] || bodies[1] != wantBodies[1] {
+		t.Errorf("Expected bodies %v, got %v", wantBodies, bodies)
+	}
+	if queries[0] != "columns=food_name%2Crating&on_conflict=food_name" {
+		t.Errorf("Unexpected query %s", queries[0])
+	}
+
+	bodies = nil
+	jsonLines := `{"id":1,"name":"a"}` + "\n" + `{"id":2}` + "\n"
+	result, err = client.Import(context.Background(), "Food", strings.NewReader(jsonLines), ImportOptions{DryRun: true})
+	if err != nil || result.Rows != 2 || len(bodies) != 0 {
+		t.Errorf("Expected dry run of 2 rows without requests, got %+v, %d requests, error %v", result, len(bodies), err)
+	}
+
+	result, err = client.Import(context.Background(), "Food", strings.NewReader(` [{"id":1},{"id":2}]`), ImportOptions{})
+	if err != nil || result.Rows != 2 || bodies[0] != `[{"id":1},{"id":2}]` {
+		t.Errorf("Expected JSON array to import as one chunk, got %+v, %v, error %v", result, bodies, err)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"bad"`) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":"22P02","message":"invalid input syntax for type integer"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token")
+
+	rows := `[{"id":1},{"id":2},{"id":"bad"}]`
+	result, err := client.Import(context.Background(), "Food", strings.NewReader(rows), ImportOptions{ChunkSize: 2})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || result.Rows != 2 {
+		t.Errorf("Expected APIError after 2 rows, got %+v and %v", result, err)
+	}
+
+	tests := []struct {
+		input string
+		opts  ImportOptions
+	}{
+		{`[1, 2]`, ImportOptions{}},
+		{`{"a":1,"b":2}`, ImportOptions{Columns: map[string]Column{"a": "b"}}},
+		{`{"a (":1}`, ImportOptions{}},
+		{"a,b\n1\n", ImportOptions{Format: ImportCSV}},
+		{`{}`, ImportOptions{Format: "xml"}},
+		{`{}`, ImportOptions{}},
+	}
+	for _, tt := range tests {
+		if _, err := client.Import(context.Background(), "Food", strings.NewReader(tt.input), tt.opts); err == nil {
+			t.Errorf("Expected error importing %q with %+v", tt.input, tt.opts)
+		}
+	}
+}
+
+func TestImportEmptyMappedRow(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token")
+
+	rows := `[{"name":"apple","notes":"x"},{"notes":"y"}]`
+	result, err := client.Import(context.Background(), "Food", strings.NewReader(rows), ImportOptions{
+		Columns: map[string]Column{"notes": ""},
+	})
+	if err == nil || !strings.Contains(err.Error(), "row 2") || result.Rows != 0 || requests != 0 {
+		t.Errorf("Expected row 2 to be refused before writing, got %+v after %d requests (%v)", result, requests, err)
+	}
+}
+
+func TestImportCodec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	codec := &countingCodec{}
+	client := NewClient(server.URL, "key", "token", WithCodec(codec))
+	if _, err := client.Import(context.Background(), "Food", strings.NewReader("id,name\n1,Udon\n"), ImportOptions{Format: ImportCSV}); err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	// Two CSV cells and the chunk.
+	if codec.marshals != 3 {
+		t.Errorf("Expected Import to encode with the codec, got %d marshals", codec.marshals)
+	}
+}
+
+func TestImportPartialRows(t *testing.T) {
+	// The fake merges like PostgREST: every column in columns= is written,
+	// as null when a row leaves it out.
+	table := map[string]map[string]json.RawMessage{
+		"1": {"id": json.RawMessage(`1`), "name": json.RawMessage(`"Ramen"`), "rating": json.RawMessage(`5`)},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var rows []map[string]json.RawMessage
+		json.NewDecoder(r.Body).Decode(&rows)
+		for _, row := range rows {
+			id := string(row["id"])
+			if table[id] == nil {
+				table[id] = map[string]json.RawMessage{}
+			}
+			for _, column := range strings.Split(r.URL.Query().Get("columns"), ",") {
+				value, ok := row[column]
+				if !ok {
+					value = json.RawMessage(`null`)
+				}
+				table[id][column] = value
+			}
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token")
+
+	rows := `{"id":1,"name":"Udon"}` + "\n" + `{"id":2,"name":"Soba","rating":3}` + "\n"
+	if _, err := client.Import(context.Background(), "Food", strings.NewReader(rows), ImportOptions{}); err != nil {
+		t.Fatalf("Import returned error: %v", err)
+	}
+	if got := table["1"]; string(got["name"]) != `"Udon"` || string(got["rating"]) != `5` {
+		t.Errorf("Expected rating of the merged row to be unchanged, got %s %s", got["name"], got["rating"])
+	}
+	if got := table["2"]; string(got["rating"]) != `3` {
+		t.Errorf("Expected new row to be inserted, got %v", got)
+	}
+}
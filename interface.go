@@ -0,0 +1,31 @@
+package supabase
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// RestClient is the request surface implemented by *Client: the table
+// methods, the context-aware writes, and the raw Execute and Do. Application
+// code can depend on it instead of the concrete type and substitute a mock in
+// tests. Builders (From, Batch) and the With* and As* options return concrete
+// types and are not part of it.
+type RestClient interface {
+	Get(endpoint string, queryParams ...map[string]string) ([]byte, error)
+	GetInto(endpoint string, dst any, queryParams ...map[string]string) error
+	GetWhere(ctx context.Context, table string, filters ...Filter) ([]byte, error)
+	Post(endpoint string, data []byte) ([]byte, error)
+	PostJSON(endpoint string, v any) ([]byte, error)
+	Put(endpoint string, primaryKeyName string, primaryKeyValue string, data []byte) ([]byte, error)
+	Patch(endpoint string, queryParams map[string]string, data []byte) ([]byte, error)
+	Delete(endpoint string, primaryKeyName string, primaryKeyValue string) ([]byte, error)
+	Insert(ctx context.Context, table string, rows []byte, columns ...Column) ([]byte, error)
+	Upsert(ctx context.Context, table string, rows []byte, opts UpsertOptions) ([]byte, error)
+	UpdateIfVersion(ctx context.Context, table string, pk, expected Filter, patch []byte) ([]byte, error)
+	DeleteWhere(ctx context.Context, table string, guard DeleteGuard, filters ...Filter) ([]byte, int, error)
+	Execute(method, endpoint string, query url.Values, body []byte) (*Response, error)
+	Do(ctx context.Context, method, path string, query url.Values, header http.Header, body []byte) (*Response, error)
+}
+
+var _ RestClient = (*Client)(nil)
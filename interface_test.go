@@ -0,0 +1,31 @@
+package supabase
+
+import "testing"
+
+type mockRestClient struct {
+	RestClient
+	gets []string
+}
+
+func (m *mockRestClient) Get(endpoint string, queryParams ...map[string]string) ([]byte, error) {
+	m.gets = append(m.gets, endpoint)
+	return []byte(`[{"id":1}]`), nil
+}
+
+func countFood(client RestClient) (int, error) {
+	body, err := client.Get("Food")
+	if err != nil {
+		return 0, err
+	}
+	return len(body), nil
+}
+
+func TestRestClientMock(t *testing.T) {
+	mock := &mockRestClient{}
+	if _, err := countFood(mock); err != nil {
+		t.Fatalf("countFood returned error: %v", err)
+	}
+	if len(mock.gets) != 1 || mock.gets[0] != "Food" {
+		t.Errorf("Expected mock Get to be called for Food, got %v", mock.gets)
+	}
+}
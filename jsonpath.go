@@ -0,0 +1,53 @@
+package supabase
+
+import "strings"
+
+// JSONPath returns a column referring to the jsonb value at path inside
+// column, using PostgREST's arrow syntax. It can be used anywhere a Column is
+// accepted:
+//
+//	client.From("profiles").
+//		Select("id", supabase.JSONText("data", "name")).
+//		Where(supabase.Eq(supabase.JSONText("data", "prefs", "theme"), "dark"))
+//	// select=id,data->>name&data->prefs->>theme=eq.dark
+//
+// Path elements made only of digits index into arrays; other elements are
+// object keys and are quoted when they contain characters outside letters,
+// digits, and underscores.
+func JSONPath(column Column, path ...string) Column {
+	return jsonPath(column, path, "->")
+}
+
+// JSONText is like JSONPath but returns the last element as text (->>), which
+// is what comparisons against plain values such as eq.dark need.
+func JSONText(column Column, path ...string) Column {
+	return jsonPath(column, path, "->>")
+}
+
+func jsonPath(column Column, path []string, last string) Column {
+	var b strings.Builder
+	b.WriteString(string(column))
+	for i, key := range path {
+		if i == len(path)-1 {
+			b.WriteString(last)
+		} else {
+			b.WriteString("->")
+		}
+		b.WriteString(jsonKey(key))
+	}
+	return Column(b.String())
+}
+
+// jsonKey quotes key for use in an arrow path unless it is an array index or
+// a plain identifier.
+func jsonKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for _, r := range key {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key) + `"`
+		}
+	}
+	return key
+}
@@ -0,0 +1,42 @@
+package supabase
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		column Column
+		want   string
+	}{
+		{JSONPath("data", "prefs"), "data->prefs"},
+		{JSONText("data", "prefs", "theme"), "data->prefs->>theme"},
+		{JSONPath("data", "tags", "0"), "data->tags->0"},
+		{JSONText("data", "my key", `a"b`), `data->"my key"->>"a\"b"`},
+		{JSONText("data"), "data"},
+	}
+	for _, tt := range tests {
+		if string(tt.column) != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, tt.column)
+		}
+	}
+}
+
+func TestJSONPathQuery(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "anon", "")
+	q := client.From("profiles").
+		Select("id", JSONText("data", "name")).
+		Where(Eq(JSONText("data", "prefs", "theme"), "dark"))
+
+	want := url.Values{
+		"select":              {"id,data->>name"},
+		"data->prefs->>theme": {"eq.dark"},
+	}
+	if got := q.Query(); got.Encode() != want.Encode() {
+		t.Errorf("Expected %s, got %s", want.Encode(), got.Encode())
+	}
+	if _, err := q.Prepare(); err != nil {
+		t.Errorf("Expected query to pass validation, got %v", err)
+	}
+}
@@ -0,0 +1,318 @@
+package supabase
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidToken is returned when a JWT is malformed or its signature does
+// not verify.
+var ErrInvalidToken = errors.New("supabase: invalid token")
+
+// ErrTokenExpired is returned when a JWT has expired.
+var ErrTokenExpired = errors.New("supabase: token expired")
+
+// TokenClaims are the claims of a Supabase access token.
+type TokenClaims struct {
+	Subject      string         `json:"sub"`
+	Role         string         `json:"role"`
+	Email        string         `json:"email"`
+	Phone        string         `json:"phone"`
+	SessionID    string         `json:"session_id"`
+	AAL          string         `json:"aal"`
+	Nonce        string         `json:"nonce"`
+	Issuer       string         `json:"iss"`
+	Audience     audience       `json:"aud"`
+	ExpiresAt    int64          `json:"exp"`
+	IssuedAt     int64          `json:"iat"`
+	NotBefore    int64          `json:"nbf"`
+	AppMetadata  map[string]any `json:"app_metadata"`
+	UserMetadata map[string]any `json:"user_metadata"`
+
+	// Raw holds every claim, including custom ones added by auth hooks.
+	Raw map[string]any `json:"-"`
+}
+
+// audience decodes the aud claim, which may be a string or an array.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// jwtHeader is the JOSE header of a JWT.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// parseJWT splits a JWT into its header, claims, signed part, and signature
+// without verifying it.
+func parseJWT(token string) (jwtHeader, *TokenClaims, string, []byte, error) {
+	var header jwtHeader
+	token = strings.TrimPrefix(token, "Bearer ")
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return header, nil, "", nil, ErrInvalidToken
+	}
+	headerJSON, err1 := base64.RawURLEncoding.DecodeString(parts[0])
+	claimsJSON, err2 := base64.RawURLEncoding.DecodeString(parts[1])
+	signature, err3 := base64.RawURLEncoding.DecodeString(parts[2])
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return header, nil, "", nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return header, nil, "", nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	claims := &TokenClaims{}
+	if err := json.Unmarshal(claimsJSON, claims); err != nil {
+		return header, nil, "", nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if err := json.Unmarshal(claimsJSON, &claims.Raw); err != nil {
+		return header, nil, "", nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return header, claims, parts[0] + "." + parts[1], signature, nil
+}
+
+// Claims decodes the claims of jwt, with or without a "Bearer " prefix,
+// WITHOUT verifying its signature. Use it to inspect tokens the client holds,
+// such as when deciding whether to refresh; use JWTVerifier for tokens
+// received from callers.
+func Claims(jwt string) (*TokenClaims, error) {
+	_, claims, _, _, err := parseJWT(jwt)
+	return claims, err
+}
+
+// TokenExpiresAt returns the expiry time of jwt, or the zero time if it has
+// no exp claim. The signature is not verified.
+func TokenExpiresAt(jwt string) (time.Time, error) {
+	claims, err := Claims(jwt)
+	if err != nil || claims.ExpiresAt == 0 {
+		return time.Time{}, err
+	}
+	return time.Unix(claims.ExpiresAt, 0), nil
+}
+
+// IsExpired reports whether jwt expires within leeway. Malformed tokens are
+// reported as expired. The signature is not verified.
+func IsExpired(jwt string, leeway time.Duration) bool {
+	claims, err := Claims(jwt)
+	if err != nil {
+		return true
+	}
+	return claims.ExpiresAt != 0 && !time.Now().Add(leeway).Before(time.Unix(claims.ExpiresAt, 0))
+}
+
+// checkTime validates the exp and nbf claims with leeway.
+func (tc *TokenClaims) checkTime(leeway time.Duration) error {
+	now := time.Now()
+	if tc.ExpiresAt != 0 && !now.Add(-leeway).Before(time.Unix(tc.ExpiresAt, 0)) {
+		return ErrTokenExpired
+	}
+	if tc.NotBefore != 0 && now.Add(leeway).Before(time.Unix(tc.NotBefore, 0)) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	return nil
+}
+
+// JWTVerifier verifies Supabase access tokens. Tokens signed with asymmetric
+// keys (RS256, ES256) are verified locally against the project's published
+// JSON Web Key Set, refetched when an unknown key ID appears so key rollover
+// needs no restart. Other tokens, and all tokens while the key set cannot be
+// fetched, are checked by asking the auth server's /user endpoint.
+type JWTVerifier struct {
+	// Leeway tolerates clock skew when checking exp and nbf.
+	Leeway time.Duration
+	// KeyTTL is how long fetched keys are trusted before being refetched.
+	KeyTTL time.Duration
+
+	client *Client
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWTVerifier returns a verifier for tokens issued by the client's project.
+func NewJWTVerifier(client *Client) *JWTVerifier {
+	return &JWTVerifier{Leeway: 30 * time.Second, KeyTTL: 10 * time.Minute, client: client}
+}
+
+// minKeyRefetch limits how often an unknown key ID triggers a refetch.
+const minKeyRefetch = 30 * time.Second
+
+// Verify checks token, with or without a "Bearer " prefix, and returns its
+// claims. Errors wrap ErrInvalidToken or ErrTokenExpired when the token
+// itself is at fault.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*TokenClaims, error) {
+	header, claims, signed, signature, err := parseJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	if err := claims.checkTime(v.Leeway); err != nil {
+		return nil, err
+	}
+	if header.Alg != "RS256" && header.Alg != "ES256" {
+		return claims, v.verifyRemote(ctx, token)
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		// Keys unavailable: fall back to the auth server.
+		return claims, v.verifyRemote(ctx, token)
+	}
+	if key == nil {
+		return nil, fmt.Errorf("%w: unknown key ID %q", ErrInvalidToken, header.Kid)
+	}
+	if err := verifySignature(header.Alg, key, signed, signature); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// key returns the public key with ID kid, refetching the key set when it is
+// stale or kid is unknown. It returns nil without error for keys the
+// published set does not contain.
+func (v *JWTVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	age := time.Since(v.fetchedAt)
+	key, ok := v.keys[kid]
+	if v.keys != nil && age < v.KeyTTL && (ok || age < minKeyRefetch) {
+		return key, nil
+	}
+	keys, err := v.fetchKeys(ctx)
+	if err != nil {
+		if v.keys != nil && ok {
+			return key, nil
+		}
+		return nil, err
+	}
+	v.keys, v.fetchedAt = keys, time.Now()
+	return keys[kid], nil
+}
+
+// jwk is a JSON Web Key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchKeys downloads the project's JSON Web Key Set.
+func (v *JWTVerifier) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	body, err := v.client.authRequest(ctx, http.MethodGet, ".well-known/jwks.json", "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse signing keys: %v", err)
+	}
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// publicKey decodes an RSA or P-256 key.
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	decode := func(s string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		return new(big.Int).SetBytes(b), err
+	}
+	switch {
+	case k.Kty == "RSA":
+		n, err1 := decode(k.N)
+		e, err2 := decode(k.E)
+		if err := errors.Join(err1, err2); err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case k.Kty == "EC" && k.Crv == "P-256":
+		x, err1 := decode(k.X)
+		y, err2 := decode(k.Y)
+		if err := errors.Join(err1, err2); err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s %s", k.Kty, k.Crv)
+}
+
+// verifySignature checks a JWS signature over signed.
+func verifySignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if alg == "RS256" && rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature) == nil {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		if alg == "ES256" && len(signature) == 64 {
+			r := new(big.Int).SetBytes(signature[:32])
+			s := new(big.Int).SetBytes(signature[32:])
+			if ecdsa.Verify(pub, digest[:], r, s) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: signature verification failed", ErrInvalidToken)
+}
+
+// verifyRemote asks the auth server whether token is valid.
+func (v *JWTVerifier) verifyRemote(ctx context.Context, token string) error {
+	_, err := v.client.authRequest(ctx, http.MethodGet, "user", "Bearer "+strings.TrimPrefix(token, "Bearer "), nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		return fmt.Errorf("%w: rejected by auth server", ErrInvalidToken)
+	}
+	return err
+}
+
+// authRequest performs a request against the auth API through the same
+// pipeline as every other request, so request hooks, signers, dry runs, and
+// the client's transport apply. authorization is sent as the Authorization
+// header in place of the client's token; none is sent when it is empty.
+func (c *Client) authRequest(ctx context.Context, method, path, authorization string, body []byte) ([]byte, error) {
+	cp := c.clone()
+	cp.rootPath = true
+	cp.Token = authorization
+	resp, err := cp.execute(ctx, method, c.serviceEndpoint(ServiceAuth, path), nil, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
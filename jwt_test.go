@@ -0,0 +1,202 @@
+package supabase
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// signJWT builds a JWT signed with key, which may be nil for HS256 tokens
+// that are signed with a dummy signature.
+func signJWT(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sig, _ = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		r, s, _ := ecdsa.Sign(rand.Reader, k, digest[:])
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	default:
+		sig = []byte("hs256-signature")
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "alg": "RS256",
+		"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString([]byte{1, 0, 1}),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256", "alg": "ES256",
+		"x": base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y": base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func TestJWTVerifier(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rotated, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	var jwks atomic.Value
+	jwks.Store([]map[string]string{rsaJWK("rsa-1", &rsaKey.PublicKey), ecJWK("ec-1", &ecKey.PublicKey)})
+	var fetches, userCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/v1/.well-known/jwks.json":
+			fetches.Add(1)
+			json.NewEncoder(w).Encode(map[string]any{"keys": jwks.Load()})
+		case "/auth/v1/user":
+			userCalls.Add(1)
+			if r.Header.Get("Authorization") == "" || r.Header.Get("apikey") != "key" {
+				t.Errorf("Expected credentials on /user call, got %v", r.Header)
+			}
+			w.Write([]byte(`{"id":"u1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	verifier := NewJWTVerifier(NewClient(server.URL, "key", ""))
+	ctx := context.Background()
+	claims := map[string]any{"sub": "u1", "role": "authenticated", "aud": "authenticated", "exp": time.Now().Add(time.Hour).Unix(), "tier": "pro"}
+
+	for _, token := range []string{
+		signJWT(t, "RS256", "rsa-1", rsaKey, claims),
+		"Bearer " + signJWT(t, "ES256", "ec-1", ecKey, claims),
+	} {
+		got, err := verifier.Verify(ctx, token)
+		if err != nil {
+			t.Fatalf("Verify returned error: %v", err)
+		}
+		if got.Subject != "u1" || got.Role != "authenticated" || got.Audience[0] != "authenticated" || got.Raw["tier"] != "pro" {
+			t.Errorf("Unexpected claims %+v", got)
+		}
+	}
+	if fetches.Load() != 1 || userCalls.Load() != 0 {
+		t.Errorf("Expected one key fetch and no /user calls, got %d and %d", fetches.Load(), userCalls.Load())
+	}
+
+	// Tampered signatures are rejected.
+	forged := signJWT(t, "ES256", "ec-1", rotated, claims)
+	if _, err := verifier.Verify(ctx, forged); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for forged token, got %v", err)
+	}
+
+	// A new key ID triggers a refetch once the refetch interval has passed.
+	jwks.Store([]map[string]string{ecJWK("ec-2", &rotated.PublicKey)})
+	verifier.mu.Lock()
+	verifier.fetchedAt = time.Now().Add(-time.Minute)
+	verifier.mu.Unlock()
+	if _, err := verifier.Verify(ctx, signJWT(t, "ES256", "ec-2", rotated, claims)); err != nil {
+		t.Errorf("Expected rotated key to verify, got %v", err)
+	}
+
+	expired := map[string]any{"sub": "u1", "exp": time.Now().Add(-time.Hour).Unix()}
+	if _, err := verifier.Verify(ctx, signJWT(t, "ES256", "ec-2", rotated, expired)); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("Expected ErrTokenExpired, got %v", err)
+	}
+
+	// Symmetric tokens are checked by the auth server.
+	if _, err := verifier.Verify(ctx, signJWT(t, "HS256", "", nil, claims)); err != nil || userCalls.Load() != 1 {
+		t.Errorf("Expected HS256 token to be verified remotely, got %v after %d calls", err, userCalls.Load())
+	}
+}
+
+func TestJWTVerifierFallback(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/v1/user" {
+			http.Error(w, `{"msg":"invalid JWT"}`, http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	verifier := NewJWTVerifier(NewClient(server.URL, "key", ""))
+	token := signJWT(t, "ES256", "ec-1", ecKey, map[string]any{"sub": "u1"})
+	if _, err := verifier.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected token rejected by /user to be invalid, got %v", err)
+	}
+	if _, err := verifier.Verify(context.Background(), "not-a-jwt"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for malformed token, got %v", err)
+	}
+}
+
+func TestJWTVerifierRequestPipeline(t *testing.T) {
+	var sent []string
+	client := NewClient("https://example.supabase.co", "key", "", WithDryRun(),
+		WithServiceURL(ServiceAuth, "https://auth.example.com/v1"),
+		WithOnRequest(func(r *PreparedRequest) {
+			sent = append(sent, r.String()+" "+r.Header.Get("Authorization"))
+		}))
+	verifier := NewJWTVerifier(client)
+
+	var dryRun *DryRunError
+	if _, err := verifier.Verify(context.Background(), signJWT(t, "HS256", "", nil, map[string]any{"sub": "u1"})); !errors.As(err, &dryRun) {
+		t.Errorf("Expected dry run error, got %v", err)
+	}
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if _, err := verifier.Verify(context.Background(), signJWT(t, "ES256", "ec-1", ecKey, map[string]any{"sub": "u1"})); !errors.As(err, &dryRun) {
+		t.Errorf("Expected dry run error, got %v", err)
+	}
+	// The failed key fetch falls back to the /user check.
+	if len(sent) != 3 || !strings.HasPrefix(sent[0], "GET https://auth.example.com/v1/user Bearer ") ||
+		sent[1] != "GET https://auth.example.com/v1/.well-known/jwks.json " || !strings.HasPrefix(sent[2], "GET https://auth.example.com/v1/user Bearer ") {
+		t.Errorf("Unexpected auth requests %q", sent)
+	}
+}
+
+func TestTokenIntrospection(t *testing.T) {
+	exp := time.Now().Add(time.Minute).Truncate(time.Second)
+	token := signJWT(t, "HS256", "", nil, map[string]any{"sub": "u1", "role": "authenticated", "exp": exp.Unix()})
+
+	claims, err := Claims("Bearer " + token)
+	if err != nil || claims.Subject != "u1" || claims.Role != "authenticated" {
+		t.Errorf("Unexpected claims %+v, %v", claims, err)
+	}
+	if got, err := TokenExpiresAt(token); err != nil || !got.Equal(exp) {
+		t.Errorf("Expected expiry %v, got %v, %v", exp, got, err)
+	}
+	if IsExpired(token, 0) {
+		t.Error("Expected token not to be expired")
+	}
+	if !IsExpired(token, 2*time.Minute) {
+		t.Error("Expected token to expire within leeway")
+	}
+	if !IsExpired("garbage", 0) {
+		t.Error("Expected malformed token to be reported as expired")
+	}
+
+	noExp := signJWT(t, "HS256", "", nil, map[string]any{"sub": "u1"})
+	if got, err := TokenExpiresAt(noExp); err != nil || !got.IsZero() || IsExpired(noExp, time.Hour) {
+		t.Errorf("Expected no expiry, got %v, %v", got, err)
+	}
+}
@@ -0,0 +1,160 @@
+package supabase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// ErrInvalidCursor is returned when a Cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("supabase: invalid cursor")
+
+// Cursor is an opaque keyset pagination token identifying the last row of a
+// page. It is safe to pass to clients and back, e.g. in a query string. The
+// empty Cursor starts at the first page.
+type Cursor string
+
+// KeysetPager pages through a query by a unique column, filtering on the last
+// key seen (id=gt.<last>) instead of skipping rows with offset, so pages stay
+// fast and stable on large tables while rows are inserted. Create one with
+// QueryBuilder.Keyset:
+//
+//	pager := client.From("Food").Select("id", "food_name").Keyset("id", 100)
+//	var rows []Food
+//	for pager.Next(ctx, &rows) {
+//		// use rows
+//	}
+//	if err := pager.Err(); err != nil {
+//		// handle err
+//	}
+type KeysetPager struct {
+	query      *QueryBuilder
+	column     Column
+	descending bool
+	size       int
+	cursor     Cursor
+	done       bool
+	capped     bool
+	err        error
+}
+
+// Keyset returns a pager over the query that orders by column, which must be
+// unique and non-null and must be among the selected columns, and fetches
+// size rows per page. Any Order, Limit, or Offset set on the query is
+// replaced. When the server caps responses below size (db-max-rows), pages
+// shrink to the cap rather than ending the iteration early.
+func (q *QueryBuilder) Keyset(column Column, size int) *KeysetPager {
+	return &KeysetPager{query: q, column: column, size: size}
+}
+
+// Descending pages from the largest key to the smallest.
+func (p *KeysetPager) Descending() *KeysetPager {
+	p.descending = true
+	return p
+}
+
+// After resumes paging after the row identified by cursor, as returned by
+// Cursor.
+func (p *KeysetPager) After(cursor Cursor) *KeysetPager {
+	p.cursor = cursor
+	return p
+}
+
+// Next fetches the next page into dst, which must be a pointer to a slice. It
+// returns false when there are no more rows or an error occurred; check Err
+// afterwards.
+func (p *KeysetPager) Next(ctx context.Context, dst any) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+	p.query.checkColumns(p.column)
+	if p.query.err != nil {
+		p.err = p.query.err
+		return false
+	}
+	query := cloneValues(p.query.query)
+	query.Del("offset")
+	query.Set("limit", strconv.Itoa(p.size))
+	direction, operator := ".asc", "gt."
+	if p.descending {
+		direction, operator = ".desc", "lt."
+	}
+	query.Set("order", string(p.column)+direction)
+	if p.cursor != "" {
+		value, err := p.cursor.value(p.query.client.getCodec())
+		if err != nil {
+			p.err = err
+			return false
+		}
+		query.Add(string(p.column), operator+value)
+	}
+
+	resp, err := p.query.client.send(ctx, http.MethodGet, p.query.table, query, nil)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	var rows []map[string]json.RawMessage
+	if err := p.query.client.getCodec().Unmarshal(resp.Body, &rows); err != nil {
+		p.err = fmt.Errorf("failed to decode response: %v", err)
+		return false
+	}
+	if len(rows) == 0 {
+		p.done = true
+		return false
+	}
+	key, ok := rows[len(rows)-1][string(p.column)]
+	if !ok {
+		p.err = fmt.Errorf("keyset column %q missing from response; add it to Select", p.column)
+		return false
+	}
+	if err := p.query.client.getCodec().Unmarshal(resp.Body, dst); err != nil {
+		p.err = fmt.Errorf("failed to decode response: %v", err)
+		return false
+	}
+	p.cursor = Cursor(base64.RawURLEncoding.EncodeToString(key))
+	if len(rows) < p.size {
+		if p.capped {
+			p.done = true
+		} else {
+			// Either the last page or the server caps pages below size
+			// (db-max-rows); continue with the smaller size to find out.
+			p.size, p.capped = len(rows), true
+		}
+	}
+	return true
+}
+
+// Cursor returns the cursor of the last row fetched, or the cursor passed to
+// After if no page has been fetched yet.
+func (p *KeysetPager) Cursor() Cursor {
+	return p.cursor
+}
+
+// Err returns the error that stopped Next, if any.
+func (p *KeysetPager) Err() error {
+	return p.err
+}
+
+// value decodes the cursor into a filter operand.
+func (c Cursor) value(codec Codec) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return "", ErrInvalidCursor
+	}
+	var key any
+	if err := codec.Unmarshal(raw, &key); err != nil {
+		return "", ErrInvalidCursor
+	}
+	switch v := key.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return string(raw), nil
+	}
+	return "", ErrInvalidCursor
+}
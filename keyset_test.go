@@ -0,0 +1,128 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestKeysetPager(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Encode())
+		switch r.URL.Query().Get("id") {
+		case "":
+			w.Write([]byte(`[{"id":1,"name":"a"},{"id":2,"name":"b"}]`))
+		case "gt.2":
+			w.Write([]byte(`[{"id":3,"name":"c"}]`))
+		case "gt.3":
+			w.Write([]byte(`[]`))
+		default:
+			t.Errorf("unexpected id filter %q", r.URL.Query().Get("id"))
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token")
+	pager := client.From("Food").Select("id", "name").Offset(40).Keyset("id", 2)
+
+	var names []string
+	var rows []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	for pager.Next(context.Background(), &rows) {
+		for _, row := range rows {
+			names = append(names, row.Name)
+		}
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatalf("Next returned error: %v", err)
+	}
+	if len(names) != 3 || names[2] != "c" {
+		t.Errorf("Expected rows a, b, c, got %v", names)
+	}
+	// A short page may mean the server caps pages, so paging only stops once
+	// a page at the smaller size is short too.
+	want := []string{"limit=2&order=id.asc&select=id%2Cname", "id=gt.2&limit=2&order=id.asc&select=id%2Cname", "id=gt.3&limit=1&order=id.asc&select=id%2Cname"}
+	if len(queries) != len(want) {
+		t.Fatalf("Expected %d requests, got %v", len(want), queries)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("Expected query %s, got %s", want[i], queries[i])
+		}
+	}
+
+	resumed := client.From("Food").Select("id", "name").Keyset("id", 2).After(Cursor("Mg")) // "2"
+	if !resumed.Next(context.Background(), &rows) || rows[0].Name != "c" {
+		t.Errorf("Expected resumed pager to return row c, got %+v (err %v)", rows, resumed.Err())
+	}
+}
+
+func TestKeysetPagerCapped(t *testing.T) {
+	// The server returns at most 2 rows per request, as with db-max-rows.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		after := 0
+		fmt.Sscanf(r.URL.Query().Get("id"), "gt.%d", &after)
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		var rows []map[string]int
+		for id := after + 1; id <= 5 && len(rows) < min(limit, 2); id++ {
+			rows = append(rows, map[string]int{"id": id})
+		}
+		json.NewEncoder(w).Encode(rows)
+	}))
+	defer server.Close()
+
+	pager := NewClient(server.URL, "key", "token").From("Food").Select("id").Keyset("id", 10)
+	var ids []int
+	var rows []struct {
+		ID int `json:"id"`
+	}
+	for pager.Next(context.Background(), &rows) {
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+		}
+	}
+	if err := pager.Err(); err != nil || len(ids) != 5 || ids[4] != 5 {
+		t.Errorf("Expected all 5 rows despite the cap, got %v (%v)", ids, err)
+	}
+}
+
+func TestKeysetPagerDescendingStringKey(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query().Get("slug") + " " + r.URL.Query().Get("order")
+		w.Write([]byte(`[{"slug":"b,c"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token")
+	pager := client.From("posts").Keyset("slug", 10).Descending()
+	var rows []map[string]any
+	pager.Next(context.Background(), &rows)
+
+	next := client.From("posts").Keyset("slug", 10).Descending().After(pager.Cursor())
+	next.Next(context.Background(), &rows)
+	if want := "lt.b,c slug.desc"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestKeysetPagerInvalidCursor(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "key", "token")
+	pager := client.From("Food").Keyset("id", 10).After("not a cursor")
+	var rows []map[string]any
+	if pager.Next(context.Background(), &rows) {
+		t.Fatal("Expected Next to fail")
+	}
+	if !errors.Is(pager.Err(), ErrInvalidCursor) {
+		t.Errorf("Expected ErrInvalidCursor, got %v", pager.Err())
+	}
+}
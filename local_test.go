@@ -0,0 +1,29 @@
+package supabase
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewLocalClient(t *testing.T) {
+	c := NewLocalClient()
+	if c.BaseUrl != LocalURL || c.ApiKey != LocalAnonKey || c.Token != "Bearer "+LocalAnonKey {
+		t.Errorf("Unexpected local client %s %s %s", c.BaseUrl, c.ApiKey, c.Token)
+	}
+	if transport := c.httpClient.Transport.(*http.Transport); transport.Proxy != nil {
+		t.Error("Expected local client to bypass proxies")
+	}
+	if got, _ := c.requestURL("Food", nil); got.String() != "http://127.0.0.1:54321/rest/v1/Food" {
+		t.Errorf("Unexpected request URL %s", got)
+	}
+}
+
+func TestWithLocalDefaults(t *testing.T) {
+	c := NewClient("https://example.supabase.co", "hosted-key", "Bearer user", WithLocalDefaults())
+	if c.BaseUrl != LocalURL || c.ApiKey != LocalAnonKey {
+		t.Errorf("Expected local URL and key, got %s %s", c.BaseUrl, c.ApiKey)
+	}
+	if c.Token != "Bearer user" {
+		t.Errorf("Expected token to be kept, got %s", c.Token)
+	}
+}
@@ -0,0 +1,52 @@
+package supabase
+
+import (
+	"context"
+	"net/http"
+)
+
+type clientContextKey struct{}
+
+// NewContext returns a copy of ctx carrying c.
+func NewContext(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, c)
+}
+
+// ClientFromContext returns the client stored by NewContext or Middleware.
+func ClientFromContext(ctx context.Context) (*Client, bool) {
+	c, ok := ctx.Value(clientContextKey{}).(*Client)
+	return c, ok
+}
+
+// Middleware returns net/http middleware that forwards the caller's
+// Authorization header to Supabase. Each request gets a clone of base made
+// with WithToken, so queries run under the caller's RLS policies, stored in
+// the request context for handlers to retrieve with ClientFromContext.
+// Requests without an Authorization header get base unchanged.
+//
+// The signature matches chi's Use; with echo use echo.WrapMiddleware, and with
+// gin wrap the handler chain around c.Request.
+func Middleware(base *Client) func(http.Handler) http.Handler {
+	return middleware(base, false)
+}
+
+// RequireToken is like Middleware but rejects requests without an
+// Authorization header with 401 Unauthorized.
+func RequireToken(base *Client) func(http.Handler) http.Handler {
+	return middleware(base, true)
+}
+
+func middleware(base *Client, required bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			client := base
+			if token := r.Header.Get("Authorization"); token != "" {
+				client = base.WithToken(token)
+			} else if required {
+				http.Error(w, "Authorization token missing", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), client)))
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package supabase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	base := NewClient("https://example.supabase.co", "key", "")
+	var got *Client
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = ClientFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Bearer user-token")
+	Middleware(base)(handler).ServeHTTP(httptest.NewRecorder(), req)
+	if got == nil || got.Token != "Bearer user-token" {
+		t.Fatalf("Expected client with forwarded token, got %+v", got)
+	}
+	if got == base || base.Token != "" || got.client() != base.client() {
+		t.Error("Expected a clone sharing the HTTP client, leaving base unchanged")
+	}
+
+	got = nil
+	Middleware(base)(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if got != base {
+		t.Errorf("Expected base client without Authorization, got %+v", got)
+	}
+
+	got = nil
+	rec := httptest.NewRecorder()
+	RequireToken(base)(handler).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusUnauthorized || got != nil {
+		t.Errorf("Expected 401 without calling handler, got %d", rec.Code)
+	}
+}
+
+func TestClientFromContextMissing(t *testing.T) {
+	if c, ok := ClientFromContext(httptest.NewRequest("GET", "/", nil).Context()); ok || c != nil {
+		t.Errorf("Expected no client, got %v", c)
+	}
+}
@@ -0,0 +1,56 @@
+package supabase
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifyNonce(t *testing.T) {
+	raw, hashed := NewNonce()
+	if hashed != HashNonce(raw) || len(hashed) != 64 {
+		t.Fatalf("Unexpected nonce pair %q %q", raw, hashed)
+	}
+
+	apple := signJWT(t, "RS256", "", nil, map[string]any{"sub": "apple-user", "nonce": hashed})
+	google := signJWT(t, "RS256", "", nil, map[string]any{"sub": "google-user", "nonce": raw})
+	if err := VerifyNonce(apple, raw); err != nil {
+		t.Errorf("Expected hashed nonce to verify, got %v", err)
+	}
+	if err := VerifyNonce(google, raw); err != nil {
+		t.Errorf("Expected raw nonce to verify, got %v", err)
+	}
+	other, _ := NewNonce()
+	if err := VerifyNonce(apple, other); !errors.Is(err, ErrNonceMismatch) {
+		t.Errorf("Expected ErrNonceMismatch, got %v", err)
+	}
+	missing := signJWT(t, "RS256", "", nil, map[string]any{"sub": "u"})
+	if err := VerifyNonce(missing, raw); !errors.Is(err, ErrNonceMismatch) {
+		t.Errorf("Expected ErrNonceMismatch without nonce claim, got %v", err)
+	}
+}
+
+func TestNonceCookie(t *testing.T) {
+	client := NewClient("https://abcdefgh.supabase.co", "key", "")
+	raw, _ := NewNonce()
+
+	rec := httptest.NewRecorder()
+	client.SetNonceCookie(rec, raw)
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "sb-abcdefgh-auth-nonce" || cookies[0].MaxAge != nonceCookieMaxAge {
+		t.Fatalf("Unexpected nonce cookie %v", cookies)
+	}
+
+	r := requestWithCookies(rec)
+	rec = httptest.NewRecorder()
+	got, err := client.TakeNonce(rec, r)
+	if err != nil || got != raw {
+		t.Errorf("Expected nonce %q, got %q, %v", raw, got, err)
+	}
+	if cleared := rec.Result().Cookies(); len(cleared) != 1 || cleared[0].MaxAge >= 0 {
+		t.Errorf("Expected nonce cookie to be cleared, got %v", cleared)
+	}
+	if _, err := client.TakeNonce(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil)); !errors.Is(err, ErrNonceMismatch) {
+		t.Errorf("Expected ErrNonceMismatch without cookie, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package supabase
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures a Client. Options are passed to NewClient.
+type Option func(*Client)
+
+// WithMaxResponseSize limits the number of bytes read from a response body.
+// Responses larger than n bytes fail with ErrResponseTooLarge instead of being
+// buffered in full, except error responses, which still fail with an APIError
+// carrying the first n bytes of the body. A value of zero or less disables the
+// limit.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *Client) {
+		c.maxResponseSize = n
+	}
+}
+
+// DialContextFunc dials a network connection. It has the signature of
+// net.Dialer.DialContext and http.Transport.DialContext.
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// WithHTTPClient sets the HTTP client used for requests. Transport options
+// such as WithDialContext and WithResolver are ignored when a custom client is
+// supplied; configure its transport directly instead.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithDialContext sets the function used to open connections to Supabase. It
+// can pin the host to fixed IPs or enforce per-connection egress policies.
+func WithDialContext(dial DialContextFunc) Option {
+	return func(c *Client) {
+		c.dialContext = dial
+	}
+}
+
+// WithResolver sets the DNS resolver used when dialing Supabase, for example
+// to use split-horizon DNS. It has no effect when WithDialContext is set.
+func WithResolver(resolver *net.Resolver) Option {
+	return func(c *Client) {
+		c.resolver = resolver
+	}
+}
+
+// WithProxy sends requests through the HTTP proxy at proxyURL instead of the
+// proxy configured in the environment. It has no effect with WithHTTPClient.
+func WithProxy(proxyURL *url.URL) Option {
+	return func(c *Client) {
+		c.proxy = proxyURL
+	}
+}
+
+// WithTimeout limits the time a single request may take, including reading
+// the response body. It has no effect with WithHTTPClient.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithSchema targets a Postgres schema other than the first one exposed by
+// PostgREST. Reads send it in the Accept-Profile header and writes in the
+// Content-Profile header; the schema must be listed in the API settings.
+func WithSchema(schema string) Option {
+	return func(c *Client) {
+		c.schema = schema
+	}
+}
+
+// newTransport builds the transport for clients created by NewClient. Each
+// client gets its own connection pool so dial hooks and warmed connections are
+// not shared with unrelated users of http.DefaultTransport.
+func (c *Client) newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	switch {
+	case c.proxy != nil:
+		transport.Proxy = http.ProxyURL(c.proxy)
+	case c.local:
+		transport.Proxy = nil
+	}
+	switch {
+	case c.dialContext != nil:
+		transport.DialContext = c.dialContext
+	case c.resolver != nil:
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Resolver:  c.resolver,
+		}
+		transport.DialContext = dialer.DialContext
+	}
+	return transport
+}
+
+// lookupResolver returns the resolver used to prime DNS.
+func (c *Client) lookupResolver() *net.Resolver {
+	if c.resolver != nil {
+		return c.resolver
+	}
+	return net.DefaultResolver
+}
@@ -0,0 +1,81 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "your_api_key", "your_token", WithMaxResponseSize(10))
+	if _, err := client.Get("Food"); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+	}
+
+	client = NewClient(server.URL, "your_api_key", "your_token", WithMaxResponseSize(100))
+	body, err := client.Get("Food")
+	if err != nil {
+		t.Fatalf("Expected no error at the limit, got %v", err)
+	}
+	if len(body) != 100 {
+		t.Errorf("Expected 100 bytes, got %d", len(body))
+	}
+}
+
+func TestWithMaxResponseSizeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"` + strings.Repeat("x", 100) + `"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "your_api_key", "your_token", WithMaxResponseSize(10))
+	_, err := client.Get("Food")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || string(apiErr.Body) != `{"message"` {
+		t.Errorf("Expected a 400 APIError with the truncated body, got %v", err)
+	}
+}
+
+func TestWithDialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var dialed []string
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		var d net.Dialer
+		return d.DialContext(ctx, network, server.Listener.Addr().String())
+	}
+
+	// The host does not resolve; the dial hook pins it to the test server.
+	client := NewClient("http://food.supabase.invalid", "your_api_key", "your_token", WithDialContext(dial))
+	if _, err := client.Get("Food"); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if err := client.Warmup(context.Background()); err != nil {
+		t.Fatalf("Warmup returned error: %v", err)
+	}
+	if len(dialed) == 0 || dialed[0] != "food.supabase.invalid:80" {
+		t.Errorf("Expected dial hook to receive the Supabase host, got %v", dialed)
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	httpClient := &http.Client{}
+	client := NewClient("https://example.supabase.co", "your_api_key", "your_token", WithHTTPClient(httpClient))
+	if client.client() != httpClient {
+		t.Error("Expected custom HTTP client to be used")
+	}
+}
@@ -0,0 +1,222 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Watermark is the position of a Poller in a table: the change column and
+// key of the last row delivered.
+type Watermark struct {
+	Value string `json:"value"`
+	Key   string `json:"key"`
+}
+
+// WatermarkStore persists a Poller's watermark between runs.
+type WatermarkStore interface {
+	// LoadWatermark returns the saved watermark, or the zero Watermark to
+	// start from the beginning of the table.
+	LoadWatermark(ctx context.Context) (Watermark, error)
+	SaveWatermark(ctx context.Context, w Watermark) error
+}
+
+// Poller delivers changed rows of a table by repeatedly querying for rows
+// whose change column (updated_at by default) is past a persisted
+// watermark, for change feeds where Realtime is not an option. Delivery is
+// at least once: the watermark is saved only after the callback succeeds, so
+// a batch is delivered again if the callback fails or the process stops
+// first. Adjust the exported fields before calling Poll or Run.
+//
+// The change column must be set on every write, e.g. by a trigger, and
+// should be indexed together with the key column.
+type Poller[T any] struct {
+	client *Client
+	table  string
+	store  WatermarkStore
+
+	// Column is the change column, updated_at by default.
+	Column Column
+	// Key breaks ties between rows with the same change value; it must be
+	// unique. It defaults to id.
+	Key Column
+	// BatchSize is the maximum number of rows per callback.
+	BatchSize int
+	// Interval is the delay between polls in Run once caught up.
+	Interval time.Duration
+}
+
+// NewPoller creates a Poller for table that keeps its watermark in store,
+// delivering up to 500 rows per batch and polling every 10 seconds.
+func NewPoller[T any](client *Client, table string, store WatermarkStore) *Poller[T] {
+	return &Poller[T]{
+		client:    client,
+		table:     table,
+		store:     store,
+		Column:    "updated_at",
+		Key:       "id",
+		BatchSize: 500,
+		Interval:  10 * time.Second,
+	}
+}
+
+// Poll delivers the rows changed since the watermark to fn in batches, in
+// change order, until caught up. It returns the number of rows delivered
+// and stops at the first error, leaving the watermark after the last batch
+// fn accepted.
+func (p *Poller[T]) Poll(ctx context.Context, fn func(ctx context.Context, rows []T) error) (int, error) {
+	delivered, _, err := p.poll(ctx, fn)
+	return delivered, err
+}
+
+// poll implements Poll and also reports whether the error was a transient
+// failure to fetch rows.
+func (p *Poller[T]) poll(ctx context.Context, fn func(ctx context.Context, rows []T) error) (int, bool, error) {
+	mark, err := p.store.LoadWatermark(ctx)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to load watermark: %v", err)
+	}
+	delivered := 0
+	for {
+		rows, next, n, err := p.fetch(ctx, mark)
+		if err != nil {
+			return delivered, isRetryable(err), err
+		}
+		if n == 0 {
+			return delivered, false, nil
+		}
+		if err := fn(ctx, rows); err != nil {
+			return delivered, false, err
+		}
+		if err := p.store.SaveWatermark(ctx, next); err != nil {
+			return delivered, false, fmt.Errorf("failed to save watermark: %v", err)
+		}
+		delivered += n
+		mark = next
+		if n < p.BatchSize {
+			return delivered, false, nil
+		}
+	}
+}
+
+// Run polls every Interval until ctx is done. Transient request failures are
+// retried on the next poll; other errors, including those returned by fn,
+// stop Run and are returned.
+func (p *Poller[T]) Run(ctx context.Context, fn func(ctx context.Context, rows []T) error) error {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+	for {
+		if _, transient, err := p.poll(ctx, fn); err != nil && !transient {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetch queries the batch after mark and returns it with the watermark of
+// its last row.
+func (p *Poller[T]) fetch(ctx context.Context, mark Watermark) ([]T, Watermark, int, error) {
+	query := url.Values{}
+	query.Set("order", string(p.Column)+".asc,"+string(p.Key)+".asc")
+	query.Set("limit", strconv.Itoa(p.BatchSize))
+	if mark.Value != "" {
+		after := Or(
+			Filter{Column: p.Column, Operator: "gt", Value: mark.Value},
+			And(Eq(p.Column, mark.Value), Filter{Column: p.Key, Operator: "gt", Value: mark.Key}),
+		)
+		key, value := after.param()
+		query.Set(key, value)
+	}
+	resp, err := p.client.send(ctx, http.MethodGet, p.table, query, nil)
+	if err != nil {
+		return nil, mark, 0, err
+	}
+	var raw []map[string]json.RawMessage
+	if err := p.client.getCodec().Unmarshal(resp.Body, &raw); err != nil {
+		return nil, mark, 0, fmt.Errorf("failed to decode response: %v", err)
+	}
+	if len(raw) == 0 {
+		return nil, mark, 0, nil
+	}
+	last := raw[len(raw)-1]
+	next := Watermark{Value: jsonScalar(last[string(p.Column)]), Key: jsonScalar(last[string(p.Key)])}
+	if next.Value == "" || next.Key == "" {
+		return nil, mark, 0, fmt.Errorf("poller columns %q and %q must be present and non-null", p.Column, p.Key)
+	}
+	var rows []T
+	if err := p.client.getCodec().Unmarshal(resp.Body, &rows); err != nil {
+		return nil, mark, 0, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return rows, next, len(raw), nil
+}
+
+// jsonScalar renders a JSON string or number as a filter operand; null and
+// missing values render as "".
+func jsonScalar(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
+}
+
+// FileWatermarkStore keeps a watermark in a JSON file, replaced atomically
+// on every save.
+type FileWatermarkStore struct {
+	Path string
+}
+
+// LoadWatermark reads the watermark, returning the zero Watermark if the file
+// does not exist yet.
+func (s FileWatermarkStore) LoadWatermark(ctx context.Context) (Watermark, error) {
+	var w Watermark
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return w, nil
+	}
+	if err != nil {
+		return w, err
+	}
+	err = json.Unmarshal(data, &w)
+	return w, err
+}
+
+// SaveWatermark writes the watermark to a temporary file and renames it over
+// the old one.
+func (s FileWatermarkStore) SaveWatermark(ctx context.Context, w Watermark) error {
+	data, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
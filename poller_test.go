@@ -0,0 +1,68 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+type pollRow struct {
+	ID int `json:"id"`
+}
+
+func TestPoller(t *testing.T) {
+	const ts = "2024-01-01T00:00:00+00:00"
+	var ors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("order"); got != "updated_at.asc,id.asc" {
+			t.Errorf("Unexpected order %s", got)
+		}
+		or := r.URL.Query().Get("or")
+		ors = append(ors, or)
+		switch or {
+		case "":
+			w.Write([]byte(`[{"id":1,"updated_at":"` + ts + `"},{"id":2,"updated_at":"` + ts + `"}]`))
+		case `(updated_at.gt."` + ts + `",and(updated_at.eq."` + ts + `",id.gt.2))`:
+			w.Write([]byte(`[{"id":3,"updated_at":"2024-01-02T00:00:00+00:00"}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	store := FileWatermarkStore{Path: filepath.Join(t.TempDir(), "watermark.json")}
+	poller := NewPoller[pollRow](NewClient(server.URL, "key", "token"), "orders", store)
+	poller.BatchSize = 2
+
+	fail := errors.New("downstream unavailable")
+	_, err := poller.Poll(context.Background(), func(ctx context.Context, rows []pollRow) error {
+		return fail
+	})
+	if !errors.Is(err, fail) {
+		t.Fatalf("Expected callback error, got %v", err)
+	}
+	if mark, _ := store.LoadWatermark(context.Background()); mark != (Watermark{}) {
+		t.Fatalf("Expected no watermark after a failed batch, got %+v", mark)
+	}
+
+	var ids []int
+	n, err := poller.Poll(context.Background(), func(ctx context.Context, rows []pollRow) error {
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+		}
+		return nil
+	})
+	if err != nil || n != 3 {
+		t.Fatalf("Expected 3 rows delivered, got %d, %v", n, err)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Errorf("Expected rows redelivered from the start, got %v", ids)
+	}
+	mark, err := store.LoadWatermark(context.Background())
+	if err != nil || mark != (Watermark{Value: "2024-01-02T00:00:00+00:00", Key: "3"}) {
+		t.Errorf("Unexpected watermark %+v (%v)", mark, err)
+	}
+}
@@ -0,0 +1,136 @@
+package supabase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// PreparedRequest is a fully constructed request as it would be sent,
+// including the authentication headers.
+type PreparedRequest struct {
+	Method string
+	URL    *url.URL
+	Header http.Header
+	Body   []byte
+}
+
+// Prepare builds the request Execute would send without performing it. Query
+// values are sent as given, as with Execute. Request signers run too, so the
+// request carries their headers, signed at the time of the call.
+func (c *Client) Prepare(method, endpoint string, query url.Values, body []byte) (*PreparedRequest, error) {
+	if err := c.validate(endpoint, query, body); err != nil {
+		return nil, err
+	}
+	prepared, err := c.prepare(method, endpoint, query, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.sign(prepared); err != nil {
+		return nil, err
+	}
+	return prepared, nil
+}
+
+// prepare builds the URL and headers of a request. Headers set on the client
+// override the defaults, and header overrides both.
+func (c *Client) prepare(method, endpoint string, query url.Values, header http.Header, body []byte) (*PreparedRequest, error) {
+	if err := c.checkServiceRoleKey(); err != nil {
+		return nil, err
+	}
+	query, body, err := c.scopeTenant(method, endpoint, query, body)
+	if err != nil {
+		return nil, err
+	}
+	reqURL, err := c.requestURL(endpoint, query)
+	if err != nil {
+		return nil, err
+	}
+	h := http.Header{}
+	h.Set("apikey", c.ApiKey)
+	if c.Token != "" {
+		h.Set("Authorization", c.Token)
+	}
+	h.Set("Content-Type", "application/json")
+	if c.schema != "" && !c.rootPath {
+		switch method {
+		case http.MethodGet, http.MethodHead:
+			h.Set("Accept-Profile", c.schema)
+		default:
+			h.Set("Content-Profile", c.schema)
+		}
+	}
+	for key, values := range c.header {
+		h[key] = values
+	}
+	for key, values := range header {
+		h[key] = values
+	}
+	return &PreparedRequest{Method: method, URL: reqURL, Header: h, Body: body}, nil
+}
+
+// newRequest creates the HTTP request for p.
+func (p *PreparedRequest) newRequest(ctx context.Context) (*http.Request, error) {
+	var reader io.Reader
+	if p.Body != nil {
+		reader = bytes.NewReader(p.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, p.Method, "", reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	u := *p.URL
+	req.URL = &u
+	req.Host = u.Host
+	req.Header = p.Header.Clone()
+	return req, nil
+}
+
+// String returns the method and URL of the request.
+func (p *PreparedRequest) String() string {
+	return p.Method + " " + p.URL.String()
+}
+
+// WithDryRun makes the client build requests without sending them. Every
+// request fails with a *DryRunError carrying the request that would have been
+// sent, which is useful for debugging and logging query construction:
+//
+//	_, err := dry.Get("Food", map[string]string{"id": "1"})
+//	var dryRun *supabase.DryRunError
+//	if errors.As(err, &dryRun) {
+//		log.Println(dryRun.Request)
+//	}
+func WithDryRun() Option {
+	return func(c *Client) {
+		c.dryRun = true
+	}
+}
+
+// WithOnRequest calls fn with every request just before it is sent,
+// including retries and hedged attempts, and before the dry-run check. It lets
+// tests assert on the final method, URL, headers, and body; combined with
+// WithDryRun no server is needed:
+//
+//	var sent *supabase.PreparedRequest
+//	client := supabase.NewClient(url, key, token, supabase.WithDryRun(),
+//		supabase.WithOnRequest(func(r *supabase.PreparedRequest) { sent = r }))
+//
+// fn must not modify the request. Multiple observers run in order.
+func WithOnRequest(fn func(*PreparedRequest)) Option {
+	return func(c *Client) {
+		c.onRequest = append(c.onRequest, fn)
+	}
+}
+
+// DryRunError is returned instead of a response by clients created with
+// WithDryRun.
+type DryRunError struct {
+	Request *PreparedRequest
+}
+
+func (e *DryRunError) Error() string {
+	return "supabase: dry run: " + e.Request.String()
+}
@@ -0,0 +1,71 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPrepare(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "key", "Bearer token").WithIdempotencyKey("abc")
+	req, err := client.From("Food").Eq("id", 1).Prepare()
+	if err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+	if got, want := req.String(), "GET https://example.supabase.co/rest/v1/Food?id=eq.1"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if req.Header.Get("apikey") != "key" || req.Header.Get("Authorization") != "Bearer token" || req.Header.Get("Idempotency-Key") != "abc" {
+		t.Errorf("Unexpected headers %v", req.Header)
+	}
+
+	if _, err := client.Prepare("GET", "Food", map[string][]string{"id": {"1"}}, nil); err == nil {
+		t.Error("Expected Prepare to validate the query")
+	}
+}
+
+func TestWithDryRun(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token", WithDryRun(), WithRetryPolicy(DefaultRetryPolicy))
+	_, err := client.Post("Food", []byte(`{"food_name":"Ramen"}`))
+	var dryRun *DryRunError
+	if !errors.As(err, &dryRun) {
+		t.Fatalf("Expected *DryRunError, got %v", err)
+	}
+	if dryRun.Request.Method != "POST" || string(dryRun.Request.Body) != `{"food_name":"Ramen"}` {
+		t.Errorf("Unexpected request %v with body %s", dryRun.Request, dryRun.Request.Body)
+	}
+
+	if _, err := client.From("Food").Execute(context.Background()); !errors.As(err, &dryRun) {
+		t.Errorf("Expected *DryRunError from builder, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests, got %d", requests)
+	}
+}
+
+func TestWithOnRequest(t *testing.T) {
+	var sent []*PreparedRequest
+	client := NewClient("https://example.supabase.co", "key", "token", WithDryRun(),
+		WithOnRequest(func(r *PreparedRequest) { sent = append(sent, r) }))
+
+	client.WithIdempotencyKey("k1").Post("Food", []byte(`{"food_name":"Ramen"}`))
+	client.From("Food").Eq("id", 1).Execute(context.Background())
+
+	if len(sent) != 2 {
+		t.Fatalf("Expected 2 observed requests, got %d", len(sent))
+	}
+	if sent[0].Method != "POST" || sent[0].Header.Get("Idempotency-Key") != "k1" || string(sent[0].Body) != `{"food_name":"Ramen"}` {
+		t.Errorf("Unexpected first request %v %v %s", sent[0], sent[0].Header, sent[0].Body)
+	}
+	if sent[1].URL.Query().Get("id") != "eq.1" {
+		t.Errorf("Unexpected second request %v", sent[1])
+	}
+}
@@ -0,0 +1,282 @@
+package supabase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// QueuedWrite is a write recorded in a WriteQueue. Header holds the headers
+// the write was sent with, such as Prefer, so it is replayed with the same
+// semantics; credentials are not recorded and are taken from the replaying
+// client instead.
+type QueuedWrite struct {
+	IdempotencyKey string      `json:"idempotency_key"`
+	Method         string      `json:"method"`
+	Endpoint       string      `json:"endpoint"`
+	Query          url.Values  `json:"query,omitempty"`
+	Header         http.Header `json:"header,omitempty"`
+	Body           []byte      `json:"body,omitempty"`
+	QueuedAt       time.Time   `json:"queued_at"`
+}
+
+// WriteQueue is a durable, file-backed queue of writes that failed because
+// Supabase could not be reached. Attach it to a client with WithWriteQueue and
+// replay it with Replay or Run once connectivity returns. Each write carries
+// an idempotency key, sent on the original attempt and on every replay, so
+// writes that reached the database before the connection failed can be
+// deduplicated server-side.
+type WriteQueue struct {
+	path string
+	mu   sync.Mutex
+	// replayMu serializes Replay calls; q.mu is only held while the file is
+	// read or written, so Enqueue is not blocked while writes are sent.
+	replayMu sync.Mutex
+
+	// OnDrop, if set, is called for queued writes that Replay discards
+	// because they failed with a non-retryable error.
+	OnDrop func(QueuedWrite, error)
+}
+
+// OpenWriteQueue opens the queue stored at path, creating the file if needed.
+func OpenWriteQueue(path string) (*WriteQueue, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open write queue: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("failed to open write queue: %v", err)
+	}
+	return &WriteQueue{path: path}, nil
+}
+
+// WithWriteQueue records POST, PUT, PATCH, and DELETE requests that fail with
+// a network error or a retryable status in q. The failed call returns an
+// error wrapping ErrWriteQueued.
+func WithWriteQueue(q *WriteQueue) Option {
+	return func(c *Client) {
+		c.writeQueue = q
+	}
+}
+
+// Enqueue appends a write to the queue and syncs it to disk.
+func (q *WriteQueue) Enqueue(w QueuedWrite) error {
+	line, err := json.Marshal(w)
+	if err != nil {
+		return fmt.Errorf("failed to encode queued write: %v", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	f, err := os.OpenFile(q.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open write queue: %v", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write queue entry: %v", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync write queue: %v", err)
+	}
+	return f.Close()
+}
+
+// Pending returns the queued writes in the order they were recorded.
+func (q *WriteQueue) Pending() ([]QueuedWrite, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.load()
+}
+
+// Replay sends queued writes in order using c and removes those that succeed.
+// It stops at the first transient failure, leaving that write and the rest in
+// the queue. Writes that fail permanently are removed, passed to OnDrop, and
+// returned joined in the error. It returns the number of writes delivered.
+// Writes enqueued while Replay runs are kept for the next replay.
+func (q *WriteQueue) Replay(ctx context.Context, c *Client) (int, error) {
+	q.replayMu.Lock()
+	defer q.replayMu.Unlock()
+
+	q.mu.Lock()
+	writes, err := q.load()
+	q.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		delivered int
+		dropped   []error
+		next      int
+		stopErr   error
+	)
+	for ; next < len(writes); next++ {
+		w := writes[next]
+		header := w.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Set(idempotencyKeyHeader, w.IdempotencyKey)
+		_, err := c.execute(ctx, w.Method, w.Endpoint, w.Query, header, w.Body)
+		if err == nil {
+			delivered++
+			continue
+		}
+		if isRetryable(err) {
+			stopErr = err
+			break
+		}
+		if q.OnDrop != nil {
+			q.OnDrop(w, err)
+		}
+		dropped = append(dropped, fmt.Errorf("dropped queued %s %s (%s): %w", w.Method, w.Endpoint, w.IdempotencyKey, err))
+	}
+
+	// Enqueue only appends, so the first next writes are still the ones
+	// replayed above.
+	q.mu.Lock()
+	current, err := q.load()
+	if err == nil {
+		err = q.store(current[min(next, len(current)):])
+	}
+	q.mu.Unlock()
+	if err != nil {
+		return delivered, err
+	}
+	if stopErr != nil {
+		dropped = append(dropped, stopErr)
+	}
+	return delivered, errors.Join(dropped...)
+}
+
+// Run replays the queue every interval until ctx is done.
+func (q *WriteQueue) Run(ctx context.Context, c *Client, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_, _ = q.Replay(ctx, c)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// load reads all queued writes. The caller must hold q.mu.
+func (q *WriteQueue) load() ([]QueuedWrite, error) {
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read write queue: %v", err)
+	}
+	var writes []QueuedWrite
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var w QueuedWrite
+		if err := json.Unmarshal(scanner.Bytes(), &w); err != nil {
+			return nil, fmt.Errorf("failed to decode write queue entry: %v", err)
+		}
+		writes = append(writes, w)
+	}
+	return writes, scanner.Err()
+}
+
+// store atomically replaces the queue contents. The caller must hold q.mu.
+func (q *WriteQueue) store(writes []QueuedWrite) error {
+	var buf bytes.Buffer
+	for _, w := range writes {
+		line, err := json.Marshal(w)
+		if err != nil {
+			return fmt.Errorf("failed to encode queued write: %v", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(q.path), filepath.Base(q.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to rewrite write queue: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to rewrite write queue: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to rewrite write queue: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to rewrite write queue: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), q.path); err != nil {
+		return fmt.Errorf("failed to rewrite write queue: %v", err)
+	}
+	return nil
+}
+
+// sendQueued performs a write with an idempotency key and records it in the
+// write queue if it fails transiently.
+func (c *Client) sendQueued(ctx context.Context, method, endpoint string, query url.Values, body []byte) (*Response, error) {
+	key := c.header.Get(idempotencyKeyHeader)
+	if key == "" {
+		key = NewIdempotencyKey()
+	}
+	header := http.Header{idempotencyKeyHeader: {key}}
+	resp, err := c.executeWithRetry(ctx, method, endpoint, query, header, body)
+	if err == nil || !isRetryable(err) {
+		return resp, err
+	}
+
+	queued := QueuedWrite{
+		IdempotencyKey: key,
+		Method:         method,
+		Endpoint:       endpoint,
+		Query:          query,
+		Header:         queuedHeader(c.header),
+		Body:           body,
+		QueuedAt:       time.Now().UTC(),
+	}
+	if qErr := c.writeQueue.Enqueue(queued); qErr != nil {
+		return nil, errors.Join(err, qErr)
+	}
+	return nil, fmt.Errorf("%w: %w", ErrWriteQueued, err)
+}
+
+// queuedHeader returns the client headers to record with a queued write,
+// leaving out credentials and the idempotency key, which is recorded on its
+// own.
+func queuedHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, key := range []string{"Authorization", "Apikey", "Cookie", idempotencyKeyHeader} {
+		header.Del(key)
+	}
+	if len(header) == 0 {
+		return nil
+	}
+	return header
+}
+
+// isWrite reports whether method modifies data.
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
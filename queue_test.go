@@ -0,0 +1,174 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteQueue(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		status = http.StatusServiceUnavailable
+		keys   []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, r.Header.Get(idempotencyKeyHeader))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	queue, err := OpenWriteQueue(filepath.Join(t.TempDir(), "writes.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenWriteQueue returned error: %v", err)
+	}
+	client := NewClient(server.URL, "your_api_key", "your_token", WithWriteQueue(queue))
+
+	if _, err := client.Post("Food", []byte(`{"food_name":"Ramen"}`)); !errors.Is(err, ErrWriteQueued) {
+		t.Fatalf("Expected ErrWriteQueued, got %v", err)
+	}
+	var apiErr *APIError
+	if _, err := client.Post("Food", []byte(`{"food_name":"Udon"}`)); !errors.As(err, &apiErr) {
+		t.Fatalf("Expected queued error to wrap the API error, got %v", err)
+	}
+
+	pending, err := queue.Pending()
+	if err != nil {
+		t.Fatalf("Pending returned error: %v", err)
+	}
+	if len(pending) != 2 || string(pending[0].Body) != `{"food_name":"Ramen"}` || pending[0].Method != http.MethodPost {
+		t.Fatalf("Unexpected pending writes %+v", pending)
+	}
+
+	// Still offline: nothing is delivered and the queue is kept.
+	if n, err := queue.Replay(context.Background(), client); n != 0 || err == nil {
+		t.Errorf("Expected replay to stop at the transient failure, got %d (%v)", n, err)
+	}
+
+	mu.Lock()
+	status = http.StatusCreated
+	keys = nil
+	mu.Unlock()
+
+	n, err := queue.Replay(context.Background(), client)
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 delivered writes, got %d (%v)", n, err)
+	}
+	if keys[0] != pending[0].IdempotencyKey || keys[1] != pending[1].IdempotencyKey {
+		t.Errorf("Expected replays to reuse the idempotency keys, got %v", keys)
+	}
+	if pending, _ := queue.Pending(); len(pending) != 0 {
+		t.Errorf("Expected queue to be empty after replay, got %+v", pending)
+	}
+}
+
+func TestWriteQueueDropsPermanentFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	queue, err := OpenWriteQueue(filepath.Join(t.TempDir(), "writes.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenWriteQueue returned error: %v", err)
+	}
+	if err := queue.Enqueue(QueuedWrite{IdempotencyKey: "abc", Method: http.MethodPost, Endpoint: "Food"}); err != nil {
+		t.Fatalf("Enqueue returned error: %v", err)
+	}
+
+	var dropped []QueuedWrite
+	queue.OnDrop = func(w QueuedWrite, err error) {
+		dropped = append(dropped, w)
+	}
+	client := NewClient(server.URL, "your_api_key", "your_token")
+	if _, err := queue.Replay(context.Background(), client); err == nil {
+		t.Error("Expected replay to report the dropped write")
+	}
+	if len(dropped) != 1 || dropped[0].IdempotencyKey != "abc" {
+		t.Errorf("Expected OnDrop to receive the write, got %+v", dropped)
+	}
+	if pending, _ := queue.Pending(); len(pending) != 0 {
+		t.Errorf("Expected dropped write to be removed, got %+v", pending)
+	}
+}
+
+func TestWriteQueueReplaysHeaders(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		online  bool
+		prefers []string
+	)
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		up := online
+		if up {
+			prefers = append(prefers, r.Header.Get("Prefer"))
+		}
+		mu.Unlock()
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	queue, err := OpenWriteQueue(filepath.Join(t.TempDir(), "writes.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenWriteQueue returned error: %v", err)
+	}
+	client := NewClient(server.URL, "your_api_key", "your_token", WithWriteQueue(queue))
+	ctx := context.Background()
+	if _, err := client.Upsert(ctx, "Food", []byte(`{"id":1}`), UpsertOptions{}); !errors.Is(err, ErrWriteQueued) {
+		t.Fatalf("Expected ErrWriteQueued, got %v", err)
+	}
+	pending, _ := queue.Pending()
+	if len(pending) != 1 || pending[0].Header.Get("Prefer") != "resolution=merge-duplicates" || pending[0].Header.Get("Authorization") != "" {
+		t.Fatalf("Expected the Prefer header to be queued without credentials, got %+v", pending)
+	}
+
+	mu.Lock()
+	online = true
+	mu.Unlock()
+	done := make(chan error)
+	go func() {
+		_, err := queue.Replay(ctx, client)
+		done <- err
+	}()
+
+	// Enqueue is not blocked while the replay waits on the server.
+	<-started
+	enqueued := make(chan error)
+	go func() {
+		enqueued <- queue.Enqueue(QueuedWrite{IdempotencyKey: "later", Method: http.MethodPost, Endpoint: "Food"})
+	}()
+	select {
+	case err := <-enqueued:
+		if err != nil {
+			t.Fatalf("Enqueue returned error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Enqueue blocked during Replay")
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Replay returned error: %v", err)
+	}
+
+	if len(prefers) != 1 || prefers[0] != "resolution=merge-duplicates" {
+		t.Errorf("Expected the upsert to be replayed with its Prefer header, got %q", prefers)
+	}
+	if pending, _ := queue.Pending(); len(pending) != 1 || pending[0].IdempotencyKey != "later" {
+		t.Errorf("Expected the write enqueued during replay to be kept, got %+v", pending)
+	}
+}
@@ -0,0 +1,71 @@
+package supabase
+
+import (
+	"strings"
+)
+
+// Range is a Postgres range literal for range operators and containment
+// filters on range columns such as tsrange or int4range:
+//
+//	supabase.Range{Lower: 9, Upper: 17}.String() // [9,17)
+//
+// A nil bound is unbounded. Bounds sets the brackets and defaults to "[)",
+// an inclusive lower and exclusive upper bound, as in Postgres.
+type Range struct {
+	Lower, Upper any
+	Bounds       string
+}
+
+// String returns the range literal, quoting bounds that contain spaces or
+// reserved characters.
+func (r Range) String() string {
+	bounds := r.Bounds
+	if len(bounds) != 2 {
+		bounds = "[)"
+	}
+	return bounds[:1] + rangeBound(r.Lower) + "," + rangeBound(r.Upper) + bounds[1:]
+}
+
+func rangeBound(v any) string {
+	if v == nil {
+		return ""
+	}
+	s := formatValue(v)
+	if s != "" && !strings.ContainsAny(s, `,()[]"\ `) {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// StrictlyLeft matches rows where the range column lies entirely before
+// value (<<), a Range or range literal:
+//
+//	supabase.StrictlyLeft("during", supabase.Range{Lower: start, Upper: end})
+//	// during=sl.[2024-05-01T09:00:00Z,2024-05-01T10:00:00Z)
+func StrictlyLeft(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "sl", Value: formatValue(value)}
+}
+
+// StrictlyRight matches rows where the range column lies entirely after
+// value (>>).
+func StrictlyRight(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "sr", Value: formatValue(value)}
+}
+
+// NotExtendRight matches rows where the range column does not extend to the
+// right of value (&<).
+func NotExtendRight(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "nxr", Value: formatValue(value)}
+}
+
+// NotExtendLeft matches rows where the range column does not extend to the
+// left of value (&>).
+func NotExtendLeft(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "nxl", Value: formatValue(value)}
+}
+
+// Adjacent matches rows where the range column is next to value without
+// overlapping it (-|-), e.g. the booking directly before or after a slot.
+func Adjacent(column Column, value any) Filter {
+	return Filter{Column: column, Operator: "adj", Value: formatValue(value)}
+}
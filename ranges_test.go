@@ -0,0 +1,33 @@
+package supabase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRangeFilters(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	slot := Range{Lower: start, Upper: start.Add(time.Hour)}
+	tests := []struct {
+		filter Filter
+		want   string
+	}{
+		{StrictlyLeft("during", slot), "during=sl.[2024-05-01T09:00:00Z,2024-05-01T10:00:00Z)"},
+		{StrictlyRight("hours", Range{Lower: 9, Upper: 17, Bounds: "[]"}), "hours=sr.[9,17]"},
+		{NotExtendRight("hours", Range{Upper: 10}), "hours=nxr.[,10)"},
+		{NotExtendLeft("hours", "(1,5]"), "hours=nxl.(1,5]"},
+		{Adjacent("during", Range{Lower: "2024-05-01 09:00", Upper: nil}), `during=adj.["2024-05-01 09:00",)`},
+		{Contains("during", slot), "during=cs.[2024-05-01T09:00:00Z,2024-05-01T10:00:00Z)"},
+		{Overlaps("hours", Range{Lower: 1, Upper: 3}), "hours=ov.[1,3)"},
+		{Not(Adjacent("hours", Range{Lower: 1, Upper: 3})), "hours=not.adj.[1,3)"},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.String(); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+		key, value := tt.filter.param()
+		if err := validateParam(key, value); err != nil {
+			t.Errorf("Expected %s to pass validation, got %v", tt.want, err)
+		}
+	}
+}
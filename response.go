@@ -0,0 +1,209 @@
+package supabase
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Response is the envelope returned by Execute. It exposes the parts of the
+// HTTP response that the []byte-returning methods discard.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Raw is the underlying HTTP response, for details the envelope does not
+	// model: the status line (Raw.Status, Raw.Proto), trailers, TLS state, and
+	// the request that was finally sent after redirects. Its body has already
+	// been read into Body and closed; Raw.Body is empty.
+	Raw *http.Response
+
+	// ServerTiming holds the metrics reported by the Supabase gateway in the
+	// Server-Timing header and the Kong latency headers, which separate
+	// database and upstream time from network time.
+	ServerTiming []ServerTiming
+}
+
+// ServerTiming is a single server-reported timing metric.
+type ServerTiming struct {
+	Name        string
+	Duration    time.Duration
+	Description string
+}
+
+// Kong reports its latencies in whole milliseconds in these headers. They are
+// exposed as ServerTiming entries under the given names.
+var proxyTimingHeaders = []struct{ header, name string }{
+	{"X-Kong-Upstream-Latency", "kong-upstream"},
+	{"X-Kong-Proxy-Latency", "kong-proxy"},
+}
+
+// newResponse builds the envelope for an HTTP response and its body.
+func newResponse(resp *http.Response, body []byte) *Response {
+	raw := *resp
+	raw.Body = http.NoBody
+	return &Response{
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header,
+		Body:         body,
+		Raw:          &raw,
+		ServerTiming: parseServerTiming(resp.Header),
+	}
+}
+
+// ContentType returns the media type of the body without parameters, e.g.
+// "text/csv" for "text/csv; charset=utf-8".
+func (r *Response) ContentType() string {
+	mediaType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";")
+	return strings.TrimSpace(mediaType)
+}
+
+// Timing returns the timing metric with the given name.
+func (r *Response) Timing(name string) (ServerTiming, bool) {
+	for _, timing := range r.ServerTiming {
+		if timing.Name == name {
+			return timing, true
+		}
+	}
+	return ServerTiming{}, false
+}
+
+// parseServerTiming parses Server-Timing headers (e.g.
+// `db;dur=53.2;desc="Database", app;dur=4`) and the Kong latency headers.
+// Malformed entries are skipped.
+func parseServerTiming(header http.Header) []ServerTiming {
+	var timings []ServerTiming
+	for _, value := range header.Values("Server-Timing") {
+		for _, metric := range splitUnquoted(value, ',') {
+			parts := splitUnquoted(metric, ';')
+			name := strings.TrimSpace(parts[0])
+			if name == "" {
+				continue
+			}
+			timing := ServerTiming{Name: name}
+			for _, param := range parts[1:] {
+				key, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+				switch strings.ToLower(strings.TrimSpace(key)) {
+				case "dur":
+					if ms, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+						timing.Duration = time.Duration(ms * float64(time.Millisecond))
+					}
+				case "desc":
+					timing.Description = unquoteParam(strings.TrimSpace(val))
+				}
+			}
+			timings = append(timings, timing)
+		}
+	}
+	for _, proxy := range proxyTimingHeaders {
+		value := header.Get(proxy.header)
+		if value == "" {
+			continue
+		}
+		if ms, err := strconv.ParseFloat(value, 64); err == nil {
+			timings = append(timings, ServerTiming{Name: proxy.name, Duration: time.Duration(ms * float64(time.Millisecond))})
+		}
+	}
+	return timings
+}
+
+// splitUnquoted splits s at each sep outside double-quoted strings, in which
+// a backslash escapes the next character.
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unquoteParam returns a header parameter value with its quotes and escapes
+// removed.
+func unquoteParam(value string) string {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value
+	}
+	var b strings.Builder
+	for i := 1; i < len(value)-1; i++ {
+		if value[i] == '\\' && i+1 < len(value)-1 {
+			i++
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
+
+// PageInfo describes the rows of a response within the full result, as
+// reported by the Content-Range header, e.g. "0-24/3573".
+type PageInfo struct {
+	// From and To are the zero-based offsets of the first and last row
+	// returned. For an empty page To is From-1.
+	From int
+	To   int
+	// Total is the number of rows matching the query, or -1 when it was not
+	// counted. Request a count with QueryBuilder.Count.
+	Total int
+	// HasMore reports whether rows follow this page. Without a count it is
+	// inferred from whether the page is as long as the request's limit, so
+	// it may be true when the last page happens to be full.
+	HasMore bool
+}
+
+// PageInfo parses the Content-Range header of the response. It returns false
+// if the header is missing or malformed.
+func (r *Response) PageInfo() (PageInfo, bool) {
+	limit := 0
+	if r.Raw != nil && r.Raw.Request != nil && r.Raw.Request.URL != nil {
+		limit, _ = strconv.Atoi(r.Raw.Request.URL.Query().Get("limit"))
+	}
+	return parseContentRange(r.Header.Get("Content-Range"), limit)
+}
+
+// parseContentRange parses a Content-Range value such as "0-24/3573",
+// "*/0", or "0-24/*". limit is the requested page size, used for HasMore
+// when the total is not counted; 0 means none.
+func parseContentRange(value string, limit int) (PageInfo, bool) {
+	rangePart, totalPart, ok := strings.Cut(value, "/")
+	if !ok {
+		return PageInfo{}, false
+	}
+	// Servers may prefix the range with its unit, e.g. "items 0-24/100".
+	if _, after, found := strings.Cut(rangePart, " "); found {
+		rangePart = after
+	}
+	info := PageInfo{To: -1, Total: -1}
+	if rangePart != "*" {
+		from, to, ok := strings.Cut(rangePart, "-")
+		if !ok {
+			return PageInfo{}, false
+		}
+		var err1, err2 error
+		info.From, err1 = strconv.Atoi(from)
+		info.To, err2 = strconv.Atoi(to)
+		if err1 != nil || err2 != nil || info.To < info.From-1 {
+			return PageInfo{}, false
+		}
+	}
+	if totalPart != "*" {
+		total, err := strconv.Atoi(totalPart)
+		if err != nil || total < 0 {
+			return PageInfo{}, false
+		}
+		info.Total = total
+		info.HasMore = info.To+1 < total
+	} else {
+		info.HasMore = limit > 0 && info.To-info.From+1 == limit
+	}
+	return info, true
+}
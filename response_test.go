@@ -0,0 +1,163 @@
+package supabase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestExecuteServerTiming(t *testing.T) {
+	var rawQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		w.Header().Add("Server-Timing", `db;dur=12.5;desc="Database", app;dur=3`)
+		w.Header().Set("X-Kong-Upstream-Latency", "20")
+		w.Header().Set("X-Kong-Proxy-Latency", "1")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "your_api_key", "your_token")
+	resp, err := client.Execute(http.MethodGet, "Food", url.Values{"rating": {"gte.4"}}, nil)
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if rawQuery != "rating=gte.4" {
+		t.Errorf("Expected query to be sent as given, got %s", rawQuery)
+	}
+	if resp.StatusCode != http.StatusOK || string(resp.Body) != "[]" {
+		t.Errorf("Unexpected response %d %s", resp.StatusCode, resp.Body)
+	}
+
+	tests := []struct {
+		name string
+		dur  time.Duration
+		desc string
+	}{
+		{"db", 12500 * time.Microsecond, "Database"},
+		{"app", 3 * time.Millisecond, ""},
+		{"kong-upstream", 20 * time.Millisecond, ""},
+		{"kong-proxy", time.Millisecond, ""},
+	}
+	for _, tt := range tests {
+		timing, ok := resp.Timing(tt.name)
+		if !ok {
+			t.Errorf("Expected timing %q to be present", tt.name)
+			continue
+		}
+		if timing.Duration != tt.dur || timing.Description != tt.desc {
+			t.Errorf("Timing %q: expected %v %q, got %v %q", tt.name, tt.dur, tt.desc, timing.Duration, timing.Description)
+		}
+	}
+}
+
+func TestParseServerTimingMalformed(t *testing.T) {
+	header := http.Header{}
+	header.Add("Server-Timing", `, cache;desc=hit, db;dur=abc`)
+	timings := parseServerTiming(header)
+	if len(timings) != 2 {
+		t.Fatalf("Expected 2 timings, got %+v", timings)
+	}
+	if timings[0].Name != "cache" || timings[0].Description != "hit" {
+		t.Errorf("Unexpected cache timing %+v", timings[0])
+	}
+	if timings[1].Name != "db" || timings[1].Duration != 0 {
+		t.Errorf("Unexpected db timing %+v", timings[1])
+	}
+}
+
+func TestParseServerTimingQuoted(t *testing.T) {
+	header := http.Header{}
+	header.Add("Server-Timing", `db;desc="a, b; c";dur=2, esc;desc="say \"hi\"", app;dur=1`)
+	timings := parseServerTiming(header)
+	if len(timings) != 3 {
+		t.Fatalf("Expected 3 timings, got %+v", timings)
+	}
+	if timings[0].Name != "db" || timings[0].Description != "a, b; c" || timings[0].Duration != 2*time.Millisecond {
+		t.Errorf("Unexpected db timing %+v", timings[0])
+	}
+	if timings[1].Description != `say "hi"` {
+		t.Errorf("Unexpected escaped description %q", timings[1].Description)
+	}
+	if timings[2].Name != "app" || timings[2].Duration != time.Millisecond {
+		t.Errorf("Unexpected app timing %+v", timings[2])
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		value string
+		limit int
+		want  PageInfo
+		ok    bool
+	}{
+		{"0-24/3573", 0, PageInfo{From: 0, To: 24, Total: 3573, HasMore: true}, true},
+		{"25-49/50", 25, PageInfo{From: 25, To: 49, Total: 50}, true},
+		{"0-24/*", 0, PageInfo{From: 0, To: 24, Total: -1}, true},
+		{"0-24/*", 25, PageInfo{From: 0, To: 24, Total: -1, HasMore: true}, true},
+		{"0-9/*", 25, PageInfo{From: 0, To: 9, Total: -1}, true},
+		{"*/0", 0, PageInfo{From: 0, To: -1, Total: 0}, true},
+		{"items 10-19/100", 0, PageInfo{From: 10, To: 19, Total: 100, HasMore: true}, true},
+		{"", 0, PageInfo{}, false},
+		{"a-b/3", 0, PageInfo{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseContentRange(tt.value, tt.limit)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseContentRange(%q, %d) = %+v, %v; expected %+v, %v", tt.value, tt.limit, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestWithAccept(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/xml" {
+			t.Errorf("Expected Accept text/xml, got %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.Write([]byte("<feed/>"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token")
+	resp, err := client.WithAccept("text/xml").From("feeds").Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if resp.ContentType() != "text/xml" || string(resp.Body) != "<feed/>" {
+		t.Errorf("Expected text/xml body, got %q %q", resp.ContentType(), resp.Body)
+	}
+	if client.header.Get("Accept") != "" {
+		t.Error("Expected the original client to be unchanged")
+	}
+}
+
+func TestResponseRaw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "X-Checksum")
+		w.Header().Set("Via", "1.1 proxy")
+		w.Write([]byte(`[]`))
+		w.Header().Set("X-Checksum", "abc")
+	}))
+	defer server.Close()
+
+	resp, err := NewClient(server.URL, "key", "token").Execute(http.MethodGet, "Food", nil, nil)
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if resp.Raw == nil || resp.Raw.Status != "200 OK" || resp.Raw.Proto != "HTTP/1.1" {
+		t.Fatalf("Expected raw status line, got %+v", resp.Raw)
+	}
+	if got := resp.Raw.Trailer.Get("X-Checksum"); got != "abc" {
+		t.Errorf("Expected trailer abc, got %q", got)
+	}
+	if resp.Raw.Header.Get("Via") != "1.1 proxy" || resp.Raw.Request.URL.Path != "/rest/v1/Food" {
+		t.Errorf("Unexpected raw response %+v", resp.Raw)
+	}
+	if n, _ := resp.Raw.Body.Read(make([]byte, 1)); n != 0 || string(resp.Body) != "[]" {
+		t.Errorf("Expected the body only in Body, got %q", resp.Body)
+	}
+}
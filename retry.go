@@ -0,0 +1,86 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Only transient
+// failures are retried: network errors, 408, 429, and 5xx responses.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 1 are treated as 1.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry. It doubles on each
+	// subsequent retry, with full jitter, up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy makes up to three attempts with a short backoff.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  100 * time.Millisecond,
+	MaxBackoff:  2 * time.Second,
+}
+
+// do calls fn until it succeeds, fails with a non-retryable error, the
+// attempts are exhausted, or ctx is done. It returns the last error.
+func (p RetryPolicy) do(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = fn(); err == nil || !isRetryable(err) || attempt+1 >= p.MaxAttempts {
+			return err
+		}
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before retry number attempt (starting at 0).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	if p.MinBackoff <= 0 {
+		return 0
+	}
+	delay := p.MinBackoff << attempt
+	if delay <= 0 || (p.MaxBackoff > 0 && delay > p.MaxBackoff) {
+		delay = p.MaxBackoff
+	}
+	return time.Duration(rand.Int64N(int64(delay) + 1))
+}
+
+// isRetryable reports whether err is a transient failure worth retrying: a
+// transport error or a 408, 429 or 5xx response other than 501. Local errors,
+// such as encoding or validation failures, are never retried.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrResponseTooLarge) {
+		return false
+	}
+	var dryRun *DryRunError
+	if errors.As(err, &dryRun) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusRequestTimeout,
+			apiErr.StatusCode == http.StatusTooManyRequests,
+			apiErr.StatusCode >= 500 && apiErr.StatusCode != http.StatusNotImplemented:
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,72 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&APIError{StatusCode: 503}, true},
+		{&APIError{StatusCode: 429}, true},
+		{&APIError{StatusCode: 408}, true},
+		{&APIError{StatusCode: 501}, false},
+		{&APIError{StatusCode: 400}, false},
+		{&APIError{StatusCode: 409}, false},
+		{fmt.Errorf("failed to perform request: %w", &url.Error{Op: "Get", URL: "http://x", Err: ErrInjectedFault}), true},
+		{fmt.Errorf("failed to read response: %w", io.ErrUnexpectedEOF), true},
+		{fmt.Errorf("failed to perform request: %w", syscall.ECONNRESET), true},
+		{fmt.Errorf("failed to encode body: %w", errors.New("unsupported value")), false},
+		{ErrTenantMismatch, false},
+		{fmt.Errorf("failed to perform request: %w", context.Canceled), false},
+		{ErrResponseTooLarge, false},
+	}
+	for _, tt := range tests {
+		if got := isRetryable(tt.err); got != tt.want {
+			t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyDo(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	calls := 0
+	err := policy.do(context.Background(), func() error {
+		calls++
+		return &APIError{StatusCode: 502}
+	})
+	if calls != 3 || err == nil {
+		t.Errorf("Expected 3 failed attempts, got %d (%v)", calls, err)
+	}
+
+	calls = 0
+	err = policy.do(context.Background(), func() error {
+		calls++
+		if calls < 2 {
+			return &APIError{StatusCode: 502}
+		}
+		return nil
+	})
+	if calls != 2 || err != nil {
+		t.Errorf("Expected success on second attempt, got %d (%v)", calls, err)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{MinBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	for attempt := 0; attempt < 70; attempt++ {
+		if delay := policy.backoff(attempt); delay < 0 || delay > 50*time.Millisecond {
+			t.Fatalf("backoff(%d) = %v out of range", attempt, delay)
+		}
+	}
+}
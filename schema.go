@@ -0,0 +1,250 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SchemaValidator compares Go structs with the table definitions PostgREST
+// publishes in its OpenAPI document, catching drift between migrations and
+// code at startup rather than on the first failing request.
+type SchemaValidator struct {
+	client *Client
+	models map[string]reflect.Type
+}
+
+// NewSchemaValidator returns a validator that reads the schema through client.
+func NewSchemaValidator(client *Client) *SchemaValidator {
+	return &SchemaValidator{client: client, models: map[string]reflect.Type{}}
+}
+
+// Register records model, a struct or pointer to struct, as the Go
+// representation of table. Columns are named by json tags as encoding/json
+// would name them.
+func (v *SchemaValidator) Register(table string, model any) *SchemaValidator {
+	v.models[table] = structType(reflect.TypeOf(model))
+	return v
+}
+
+// MismatchKind classifies a SchemaMismatch.
+type MismatchKind string
+
+const (
+	// MissingTable means the table or view is not exposed by the API.
+	MissingTable MismatchKind = "missing table"
+	// MissingColumn means a struct field has no matching column.
+	MissingColumn MismatchKind = "missing column"
+	// UnmappedColumn means a required column has no struct field, so
+	// inserts built from the struct will fail.
+	UnmappedColumn MismatchKind = "unmapped required column"
+)
+
+// SchemaMismatch is a difference between a registered struct and the schema.
+type SchemaMismatch struct {
+	Kind   MismatchKind
+	Table  string
+	Column string
+	// Field is the Go field, for MissingColumn.
+	Field string
+	// Suggestion is a similarly named column the field may have been
+	// renamed to, if any.
+	Suggestion string
+}
+
+func (m SchemaMismatch) String() string {
+	switch m.Kind {
+	case MissingTable:
+		return fmt.Sprintf("%s: %s", m.Table, m.Kind)
+	case MissingColumn:
+		s := fmt.Sprintf("%s.%s: %s for field %s", m.Table, m.Column, m.Kind, m.Field)
+		if m.Suggestion != "" {
+			s += fmt.Sprintf(" (renamed to %s?)", m.Suggestion)
+		}
+		return s
+	}
+	return fmt.Sprintf("%s.%s: %s", m.Table, m.Column, m.Kind)
+}
+
+// SchemaError is returned by Validate when registered structs do not match
+// the schema.
+type SchemaError struct {
+	Mismatches []SchemaMismatch
+}
+
+func (e *SchemaError) Error() string {
+	lines := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		lines[i] = m.String()
+	}
+	return "supabase: schema mismatch:\n  " + strings.Join(lines, "\n  ")
+}
+
+// openAPISchema is the part of the PostgREST OpenAPI document that describes
+// tables and views.
+type openAPISchema struct {
+	Definitions map[string]struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+		Required   []string                   `json:"required"`
+	} `json:"definitions"`
+}
+
+// Validate fetches the OpenAPI document and compares it with the registered
+// structs. It returns a *SchemaError listing every mismatch, or nil.
+func (v *SchemaValidator) Validate(ctx context.Context) error {
+	header := http.Header{"Accept": {"application/openapi+json"}}
+	resp, err := v.client.execute(ctx, http.MethodGet, "", nil, header, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch schema: %w", err)
+	}
+	var schema openAPISchema
+	if err := json.Unmarshal(resp.Body, &schema); err != nil {
+		return fmt.Errorf("failed to parse schema: %v", err)
+	}
+
+	tables := make([]string, 0, len(v.models))
+	for table := range v.models {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	var mismatches []SchemaMismatch
+	for _, table := range tables {
+		def, ok := schema.Definitions[table]
+		if !ok {
+			mismatches = append(mismatches, SchemaMismatch{Kind: MissingTable, Table: table})
+			continue
+		}
+		fields := structColumns(v.models[table])
+		mapped := map[string]bool{}
+		var unmatched []string
+		for column := range def.Properties {
+			if _, ok := fields[column]; !ok {
+				unmatched = append(unmatched, column)
+			}
+		}
+		sort.Strings(unmatched)
+
+		columns := make([]string, 0, len(fields))
+		for column := range fields {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			if _, ok := def.Properties[column]; ok {
+				mapped[column] = true
+				continue
+			}
+			mismatches = append(mismatches, SchemaMismatch{
+				Kind:       MissingColumn,
+				Table:      table,
+				Column:     column,
+				Field:      fields[column],
+				Suggestion: similarColumn(column, unmatched),
+			})
+		}
+		required := slices.Clone(def.Required)
+		sort.Strings(required)
+		for _, column := range required {
+			if !mapped[column] {
+				mismatches = append(mismatches, SchemaMismatch{Kind: UnmappedColumn, Table: table, Column: column})
+			}
+		}
+	}
+	if len(mismatches) > 0 {
+		return &SchemaError{Mismatches: mismatches}
+	}
+	return nil
+}
+
+// structType dereferences pointer types.
+func structType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// structColumns maps the JSON names of a struct's fields to the Go field
+// names, following embedded structs like encoding/json.
+func structColumns(t reflect.Type) map[string]string {
+	columns := map[string]string{}
+	if t == nil || t.Kind() != reflect.Struct {
+		return columns
+	}
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || len(f.Index) > 1 && !embeddedPath(t, f.Index) {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && structType(f.Type).Kind() == reflect.Struct {
+			// Promoted fields are listed separately by VisibleFields.
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		columns[name] = f.Name
+	}
+	return columns
+}
+
+// embeddedPath reports whether every struct along index is embedded without a
+// json name, so its fields are promoted in the JSON encoding.
+func embeddedPath(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.Anonymous || name != "" {
+			return false
+		}
+		t = structType(f.Type)
+	}
+	return true
+}
+
+// similarColumn returns the candidate that column was most likely renamed to,
+// or "" when none is close.
+func similarColumn(column string, candidates []string) string {
+	normalize := func(s string) string { return strings.ToLower(strings.ReplaceAll(s, "_", "")) }
+	best, bestDist := "", 3
+	for _, candidate := range candidates {
+		if normalize(candidate) == normalize(column) {
+			return candidate
+		}
+		if d := editDistance(column, candidate); d < bestDist {
+			best, bestDist = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
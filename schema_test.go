@@ -0,0 +1,93 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const testOpenAPI = `{
+  "swagger": "2.0",
+  "definitions": {
+    "Food": {
+      "required": ["id", "food_name"],
+      "properties": {
+        "id": {"type": "integer"},
+        "food_name": {"type": "string"},
+        "rating": {"type": "integer"},
+        "created_at": {"type": "string"}
+      }
+    }
+  }
+}`
+
+type audit struct {
+	CreatedAt string `json:"createdAt"`
+}
+
+type food struct {
+	audit
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Rating int    `json:"rating,omitempty"`
+	Notes  string `json:"-"`
+	secret string
+}
+
+func TestSchemaValidator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/v1/" || r.Header.Get("Accept") != "application/openapi+json" {
+			t.Errorf("Unexpected schema request %s with Accept %q", r.URL.Path, r.Header.Get("Accept"))
+		}
+		w.Write([]byte(testOpenAPI))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token")
+	err := NewSchemaValidator(client).
+		Register("Food", &food{}).
+		Register("Drinks", food{}).
+		Validate(context.Background())
+
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("Expected *SchemaError, got %v", err)
+	}
+	want := []SchemaMismatch{
+		{Kind: MissingTable, Table: "Drinks"},
+		{Kind: MissingColumn, Table: "Food", Column: "createdAt", Field: "CreatedAt", Suggestion: "created_at"},
+		{Kind: MissingColumn, Table: "Food", Column: "name", Field: "Name"},
+		{Kind: UnmappedColumn, Table: "Food", Column: "food_name"},
+	}
+	if !reflect.DeepEqual(schemaErr.Mismatches, want) {
+		t.Errorf("Expected mismatches %+v, got %+v", want, schemaErr.Mismatches)
+	}
+
+	type matching struct {
+		ID       int    `json:"id"`
+		FoodName string `json:"food_name"`
+	}
+	if err := NewSchemaValidator(client).Register("Food", matching{}).Validate(context.Background()); err != nil {
+		t.Errorf("Expected no mismatches, got %v", err)
+	}
+}
+
+func TestSimilarColumn(t *testing.T) {
+	tests := []struct {
+		column     string
+		candidates []string
+		want       string
+	}{
+		{"foodName", []string{"food_name", "rating"}, "food_name"},
+		{"ratng", []string{"rating"}, "rating"},
+		{"name", []string{"food_name"}, ""},
+	}
+	for _, tt := range tests {
+		if got := similarColumn(tt.column, tt.candidates); got != tt.want {
+			t.Errorf("similarColumn(%q) = %q, want %q", tt.column, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package supabase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrServiceRoleKeyMisuse is returned when a client configured with
+// WithServiceRoleKey would send the service role key without having been
+// elevated with AsServiceRole, for example because it was passed as the user
+// token by mistake.
+var ErrServiceRoleKeyMisuse = errors.New("supabase: service role key used outside AsServiceRole")
+
+// ErrNoServiceRoleKey is returned by requests of a client elevated with
+// AsServiceRole when no service role key was configured.
+var ErrNoServiceRoleKey = errors.New("supabase: AsServiceRole requires WithServiceRoleKey")
+
+// WithServiceRoleKey configures the project's service role key alongside the
+// anon key passed to NewClient. Requests keep using the anon key and user
+// token until a copy is elevated with AsServiceRole, and the service role key
+// is refused anywhere else.
+func WithServiceRoleKey(key string) Option {
+	return func(c *Client) {
+		c.serviceRoleKey = key
+	}
+}
+
+// AsServiceRole returns a copy of the client that authenticates with the
+// service role key, bypassing RLS. Use it for the specific calls that need
+// elevated access:
+//
+//	client.AsServiceRole().Delete("sessions", "user_id", id)
+//
+// If the client has no service role key, every request of the copy fails with
+// ErrNoServiceRoleKey, since a silent fallback to the anon key would only
+// surface later as confusing RLS failures.
+func (c *Client) AsServiceRole() *Client {
+	cp := c.clone()
+	cp.elevated = true
+	cp.asUser = false
+	if c.serviceRoleKey == "" {
+		return cp
+	}
+	cp.ApiKey = c.serviceRoleKey
+	cp.Token = "Bearer " + c.serviceRoleKey
+	return cp
+}
+
+// checkServiceRoleKey enforces the WithServiceRoleKey guardrail.
+func (c *Client) checkServiceRoleKey() error {
+	switch {
+	case c.elevated && c.serviceRoleKey == "":
+		return ErrNoServiceRoleKey
+	case c.serviceRoleKey == "" || c.elevated:
+		return nil
+	case strings.Contains(c.Token, c.serviceRoleKey):
+		return ErrServiceRoleKeyMisuse
+	case c.ApiKey == c.serviceRoleKey && !c.asUser:
+		return ErrServiceRoleKeyMisuse
+	}
+	return nil
+}
+
+// AsUser returns a copy of the client that sends the service role key as the
+// API key but token, a user's access token, as the Authorization header.
+// PostgREST takes the database role from the Authorization JWT, so requests
+// run as that user under RLS, while the gateway sees the service key; backend
+// jobs use this to act on a user's behalf without widening their access. The
+// token may include the "Bearer " prefix. Without a service role key only
+// the token changes, as with WithToken.
+func (c *Client) AsUser(token string) *Client {
+	cp := c.clone()
+	if c.serviceRoleKey != "" {
+		cp.ApiKey = c.serviceRoleKey
+	}
+	cp.Token = "Bearer " + strings.TrimPrefix(token, "Bearer ")
+	cp.elevated = false
+	cp.asUser = true
+	return cp
+}
+
+// WithJWTSecret sets the project's legacy JWT secret, letting AsUserID mint
+// short-lived user tokens. Projects that sign with asymmetric keys must pass
+// real user tokens to AsUser instead.
+func WithJWTSecret(secret string) Option {
+	return func(c *Client) {
+		c.jwtSecret = secret
+	}
+}
+
+// userTokenTTL is the lifetime of tokens minted by AsUserID.
+const userTokenTTL = 5 * time.Minute
+
+// AsUserID is like AsUser for a user ID rather than a token: it mints an
+// HS256 token for the user with the "authenticated" role using the secret set
+// by WithJWTSecret, so RLS policies based on auth.uid() apply. It returns an
+// error when no JWT secret is configured.
+func (c *Client) AsUserID(userID string) (*Client, error) {
+	if c.jwtSecret == "" {
+		return nil, errors.New("supabase: AsUserID requires WithJWTSecret")
+	}
+	now := time.Now()
+	token, err := signHS256(c.jwtSecret, map[string]any{
+		"sub":  userID,
+		"role": "authenticated",
+		"aud":  "authenticated",
+		"iat":  now.Unix(),
+		"exp":  now.Add(userTokenTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.AsUser(token), nil
+}
+
+// signHS256 returns a JWT with claims signed with secret.
+func signHS256(secret string, claims map[string]any) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,98 @@
+package supabase
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAsServiceRole(t *testing.T) {
+	var apikey, authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apikey, authorization = r.Header.Get("apikey"), r.Header.Get("Authorization")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "anon", "Bearer user-jwt", WithServiceRoleKey("service"))
+	if _, err := client.Get("Food"); err != nil || apikey != "anon" || authorization != "Bearer user-jwt" {
+		t.Errorf("Expected anon request, got %q %q %v", apikey, authorization, err)
+	}
+	if _, err := client.AsServiceRole().Get("Food"); err != nil || apikey != "service" || authorization != "Bearer service" {
+		t.Errorf("Expected service role request, got %q %q %v", apikey, authorization, err)
+	}
+	if client.ApiKey != "anon" {
+		t.Error("Expected AsServiceRole to leave the original client unchanged")
+	}
+
+	// The service key passed as a user token is refused.
+	if _, err := client.WithToken("Bearer service").Get("Food"); !errors.Is(err, ErrServiceRoleKeyMisuse) {
+		t.Errorf("Expected ErrServiceRoleKeyMisuse, got %v", err)
+	}
+}
+
+func TestAsServiceRoleWithoutKey(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "anon", "", WithDryRun())
+	if _, err := client.AsServiceRole().Get("Food"); !errors.Is(err, ErrNoServiceRoleKey) {
+		t.Errorf("Expected ErrNoServiceRoleKey, got %v", err)
+	}
+	if _, err := client.Prepare("GET", "Food", nil, nil); err != nil {
+		t.Errorf("Expected the original client to keep working, got %v", err)
+	}
+}
+
+func TestNewClientFromEnvServiceRole(t *testing.T) {
+	t.Setenv(EnvURL, "https://example.supabase.co")
+	t.Setenv(EnvAnonKey, "anon")
+	t.Setenv(EnvServiceRoleKey, "service")
+	c, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ApiKey != "anon" || c.AsServiceRole().ApiKey != "service" {
+		t.Errorf("Expected anon key with service role elevation, got %s", c.ApiKey)
+	}
+}
+
+func TestAsUser(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "anon", "", WithServiceRoleKey("service"), WithDryRun())
+
+	req, err := client.AsUser("user-jwt").Prepare("GET", "Food", nil, nil)
+	if err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+	if req.Header.Get("apikey") != "service" || req.Header.Get("Authorization") != "Bearer user-jwt" {
+		t.Errorf("Expected service apikey with user token, got %v", req.Header)
+	}
+
+	if _, err := client.AsUser("service").Prepare("GET", "Food", nil, nil); !errors.Is(err, ErrServiceRoleKeyMisuse) {
+		t.Errorf("Expected service key as user token to be refused, got %v", err)
+	}
+	if _, err := client.AsServiceRole().AsUser("user-jwt").Prepare("GET", "Food", nil, nil); err != nil {
+		t.Errorf("Expected AsUser after AsServiceRole to work, got %v", err)
+	}
+}
+
+func TestAsUserID(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "anon", "", WithServiceRoleKey("service"), WithJWTSecret("super-secret-jwt-token"))
+	if _, err := NewClient("https://example.supabase.co", "anon", "").AsUserID("u1"); err == nil {
+		t.Error("Expected error without a JWT secret")
+	}
+
+	scoped, err := client.AsUserID("u1")
+	if err != nil {
+		t.Fatalf("AsUserID returned error: %v", err)
+	}
+	_, claims, signed, signature, err := parseJWT(scoped.Token)
+	if err != nil {
+		t.Fatalf("Expected a valid JWT, got %v", err)
+	}
+	if claims.Subject != "u1" || claims.Role != "authenticated" || claims.checkTime(0) != nil {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+	want, _ := signHS256("super-secret-jwt-token", claims.Raw)
+	if _, _, _, wantSig, _ := parseJWT(want); string(wantSig) != string(signature) || signed == "" {
+		t.Error("Expected token signed with the JWT secret")
+	}
+}
@@ -0,0 +1,105 @@
+package supabase
+
+import "strings"
+
+// Service identifies one of the APIs of a Supabase project.
+type Service string
+
+const (
+	ServiceREST      Service = "rest"
+	ServiceAuth      Service = "auth"
+	ServiceStorage   Service = "storage"
+	ServiceFunctions Service = "functions"
+	ServiceRealtime  Service = "realtime"
+)
+
+// services lists every Service with its default path under the project URL.
+var services = []struct {
+	service Service
+	path    string
+}{
+	{ServiceREST, restApiPath},
+	{ServiceAuth, authApiPath},
+	{ServiceStorage, "/storage/v1"},
+	{ServiceFunctions, "/functions/v1"},
+	{ServiceRealtime, "/realtime/v1"},
+}
+
+// WithServiceURL serves service from baseURL instead of its standard path
+// under the project URL, for self-hosted deployments where services run on
+// separate hosts:
+//
+//	client := supabase.NewClient("https://supabase.internal", key, token,
+//		supabase.WithServiceURL(supabase.ServiceREST, "https://rest.internal"),
+//		supabase.WithServiceURL(supabase.ServiceAuth, "https://auth.internal/v1"))
+//
+// baseURL replaces the project URL and the service path together: REST
+// requests for "Food" go to https://rest.internal/Food.
+func WithServiceURL(service Service, baseURL string) Option {
+	return func(c *Client) {
+		if c.serviceURLs == nil {
+			c.serviceURLs = map[Service]string{}
+		}
+		c.serviceURLs[service] = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithServicePath changes the path of service under the project URL, e.g.
+// from /rest/v1 to /api/rest, for gateways that mount services elsewhere or
+// newer API versions:
+//
+//	supabase.WithServicePath(supabase.ServiceREST, "/pgrst/v2")
+//
+// It has no effect on a service whose URL is set with WithServiceURL.
+func WithServicePath(service Service, path string) Option {
+	return func(c *Client) {
+		if c.servicePaths == nil {
+			c.servicePaths = map[Service]string{}
+		}
+		c.servicePaths[service] = "/" + strings.Trim(path, "/")
+	}
+}
+
+// ServiceURL returns the base URL of service, e.g.
+// https://xyz.supabase.co/rest/v1.
+func (c *Client) ServiceURL(service Service) string {
+	if u, ok := c.serviceURLs[service]; ok {
+		return u
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(c.BaseUrl, "/")+c.servicePath(service), "/")
+}
+
+// servicePath returns the path of service under the project URL.
+func (c *Client) servicePath(service Service) string {
+	if path, ok := c.servicePaths[service]; ok {
+		return path
+	}
+	for _, s := range services {
+		if s.service == service {
+			return s.path
+		}
+	}
+	return ""
+}
+
+// serviceEndpoint returns the path of endpoint under service relative to the
+// project URL, as resolved by resolvePath.
+func (c *Client) serviceEndpoint(service Service, endpoint string) string {
+	return strings.TrimPrefix(c.servicePath(service), "/") + "/" + endpoint
+}
+
+// resolvePath splits a path relative to the project URL, as passed to Do,
+// into the base URL of the service it belongs to and the rest of the path,
+// so overridden services are reached at their own hosts.
+func (c *Client) resolvePath(path string) (base, rest string) {
+	for _, s := range services {
+		if _, ok := c.serviceURLs[s.service]; !ok {
+			continue
+		}
+		prefix := strings.TrimPrefix(c.servicePath(s.service), "/")
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return c.ServiceURL(s.service), strings.TrimPrefix(path[len(prefix):], "/")
+		}
+	}
+	return c.BaseUrl, path
+}
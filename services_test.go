@@ -0,0 +1,86 @@
+package supabase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServiceURL(t *testing.T) {
+	client := NewClient("https://xyz.supabase.co/", "key", "token",
+		WithServiceURL(ServiceAuth, "https://auth.internal/v1/"))
+
+	tests := []struct {
+		service Service
+		want    string
+	}{
+		{ServiceREST, "https://xyz.supabase.co/rest/v1"},
+		{ServiceAuth, "https://auth.internal/v1"},
+		{ServiceStorage, "https://xyz.supabase.co/storage/v1"},
+		{ServiceFunctions, "https://xyz.supabase.co/functions/v1"},
+		{ServiceRealtime, "https://xyz.supabase.co/realtime/v1"},
+	}
+	for _, tt := range tests {
+		if got := client.ServiceURL(tt.service); got != tt.want {
+			t.Errorf("ServiceURL(%s) = %s, expected %s", tt.service, got, tt.want)
+		}
+	}
+}
+
+func TestServiceURLRequests(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Host+r.URL.Path)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	host := server.Listener.Addr().String()
+
+	client := NewClient("https://unused.invalid", "key", "token",
+		WithServiceURL(ServiceREST, server.URL+"/pgrst"),
+		WithServiceURL(ServiceFunctions, server.URL))
+	if _, err := client.Get("Food"); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if _, err := client.Do(context.Background(), http.MethodPost, "/functions/v1/hello", nil, nil, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if _, err := client.Do(context.Background(), http.MethodGet, "/rest/v1/Food", nil, nil, nil); err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	want := []string{host + "/pgrst/Food", host + "/hello", host + "/pgrst/Food"}
+	if len(paths) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("Expected request to %s, got %s", want[i], paths[i])
+		}
+	}
+}
+
+func TestServicePath(t *testing.T) {
+	client := NewClient("https://gw.example.com", "key", "token",
+		WithServicePath(ServiceREST, "pgrst/v2/"),
+		WithServicePath(ServiceAuth, "/"),
+		WithServicePath(ServiceStorage, "/files"),
+		WithServiceURL(ServiceStorage, "https://files.example.com"))
+
+	if got, want := client.ServiceURL(ServiceREST), "https://gw.example.com/pgrst/v2"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if got, want := client.ServiceURL(ServiceAuth), "https://gw.example.com"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if got, want := client.ServiceURL(ServiceStorage), "https://files.example.com"; got != want {
+		t.Errorf("Expected WithServiceURL to take precedence, got %s", got)
+	}
+	u, err := client.From("Food").BuildURL()
+	if err != nil {
+		t.Fatalf("BuildURL returned error: %v", err)
+	}
+	if got, want := u.String(), "https://gw.example.com/pgrst/v2/Food"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
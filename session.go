@@ -0,0 +1,139 @@
+package supabase
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// Session is a signed-in GoTrue session as returned by the token endpoints.
+// It round-trips through JSON without loss, including fields this package
+// does not model, so it can be kept in Redis or an encrypted cookie and
+// restored with WithSession.
+type Session struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	ExpiresAt    int64  `json:"expires_at"`
+	RefreshToken string `json:"refresh_token"`
+	User         *User  `json:"user,omitempty"`
+
+	// ProviderToken and ProviderRefreshToken are the upstream OAuth
+	// provider's tokens, returned after an OAuth sign-in so backends can call
+	// the provider's API (e.g. Google Calendar) for the user. GoTrue does not
+	// store them, so they are only present in the session from the sign-in.
+	ProviderToken        string `json:"provider_token,omitempty"`
+	ProviderRefreshToken string `json:"provider_refresh_token,omitempty"`
+
+	extra map[string]json.RawMessage
+}
+
+// User is a GoTrue user. Like Session, it keeps unmodelled fields such as
+// identities across a JSON round trip.
+type User struct {
+	ID           string         `json:"id"`
+	Aud          string         `json:"aud"`
+	Role         string         `json:"role"`
+	Email        string         `json:"email,omitempty"`
+	Phone        string         `json:"phone,omitempty"`
+	AppMetadata  map[string]any `json:"app_metadata,omitempty"`
+	UserMetadata map[string]any `json:"user_metadata,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+
+	extra map[string]json.RawMessage
+}
+
+// Expired reports whether the access token expires within leeway. Sessions
+// without an expiry time never expire.
+func (s *Session) Expired(leeway time.Duration) bool {
+	return s.ExpiresAt != 0 && !time.Now().Add(leeway).Before(time.Unix(s.ExpiresAt, 0))
+}
+
+// WithSession returns a copy of the client authenticated with the session's
+// access token.
+func (c *Client) WithSession(s *Session) *Client {
+	return c.WithToken("Bearer " + s.AccessToken)
+}
+
+// MarshalJSON encodes the session with any fields kept from decoding.
+func (s Session) MarshalJSON() ([]byte, error) {
+	type plain Session
+	return marshalWithExtra(plain(s), s.extra)
+}
+
+// UnmarshalJSON decodes a session, keeping unknown fields. ExpiresAt is
+// derived from ExpiresIn when the response omits it.
+func (s *Session) UnmarshalJSON(data []byte) error {
+	type plain Session
+	var v plain
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	*s = Session(v)
+	s.extra = extra
+	if s.ExpiresAt == 0 && s.ExpiresIn > 0 {
+		s.ExpiresAt = time.Now().Unix() + int64(s.ExpiresIn)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the user with any fields kept from decoding.
+func (u User) MarshalJSON() ([]byte, error) {
+	type plain User
+	return marshalWithExtra(plain(u), u.extra)
+}
+
+// UnmarshalJSON decodes a user, keeping unknown fields.
+func (u *User) UnmarshalJSON(data []byte) error {
+	type plain User
+	var v plain
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	extra, err := unknownFields(data, reflect.TypeOf(v))
+	if err != nil {
+		return err
+	}
+	*u = User(v)
+	u.extra = extra
+	return nil
+}
+
+// marshalWithExtra encodes v and merges in extra fields it does not set.
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range extra {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// unknownFields returns the fields of the JSON object data that do not map
+// to a field of struct type t.
+func unknownFields(data []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name := range structColumns(t) {
+		delete(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
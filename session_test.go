@@ -0,0 +1,72 @@
+package supabase
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+const tokenResponse = `{
+  "access_token": "access",
+  "token_type": "bearer",
+  "expires_in": 3600,
+  "expires_at": 1700000000,
+  "refresh_token": "refresh",
+  "provider_token": "gh-token",
+  "provider_refresh_token": "gh-refresh",
+  "user": {
+    "id": "u1",
+    "aud": "authenticated",
+    "role": "authenticated",
+    "email": "ann@example.com",
+    "app_metadata": {"provider": "github"},
+    "user_metadata": {"name": "Ann"},
+    "identities": [{"provider": "github", "id": "42"}],
+    "created_at": "2024-05-01T12:00:00Z",
+    "updated_at": "2024-05-02T12:00:00Z"
+  }
+}`
+
+func TestSessionJSONRoundTrip(t *testing.T) {
+	var s Session
+	if err := json.Unmarshal([]byte(tokenResponse), &s); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if s.ProviderToken != "gh-token" || s.ProviderRefreshToken != "gh-refresh" {
+		t.Errorf("Expected provider tokens, got %q %q", s.ProviderToken, s.ProviderRefreshToken)
+	}
+	if s.AccessToken != "access" || s.ExpiresAt != 1700000000 || s.User.Email != "ann@example.com" || s.User.UserMetadata["name"] != "Ann" {
+		t.Errorf("Unexpected session %+v", s)
+	}
+
+	data, err := json.Marshal(&s)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	var want, got map[string]any
+	json.Unmarshal([]byte(tokenResponse), &want)
+	json.Unmarshal(data, &got)
+	wantJSON, _ := json.Marshal(want)
+	gotJSON, _ := json.Marshal(got)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("Expected round trip to keep all fields:\nwant %s\ngot  %s", wantJSON, gotJSON)
+	}
+}
+
+func TestSessionExpiresAtFromExpiresIn(t *testing.T) {
+	var s Session
+	json.Unmarshal([]byte(`{"access_token":"a","expires_in":60}`), &s)
+	if d := time.Until(time.Unix(s.ExpiresAt, 0)); d < 55*time.Second || d > 61*time.Second {
+		t.Errorf("Expected ExpiresAt about a minute ahead, got %v", d)
+	}
+	if s.Expired(0) || !s.Expired(2*time.Minute) {
+		t.Error("Unexpected Expired result")
+	}
+}
+
+func TestWithSession(t *testing.T) {
+	c := NewClient("https://example.supabase.co", "key", "")
+	if got := c.WithSession(&Session{AccessToken: "a"}).Token; got != "Bearer a" {
+		t.Errorf("Expected Bearer a, got %s", got)
+	}
+}
@@ -0,0 +1,111 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHMACSigner(t *testing.T) {
+	const secret = "gateway-secret"
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := VerifyHMACSignature(r, body, "X-Signature", secret); err != nil {
+			t.Errorf("Expected %s %s to be signed, got %v", r.Method, r.URL, err)
+		}
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var observed string
+	client := NewClient(server.URL, "key", "token",
+		WithRequestSigner(HMACSigner("X-Signature", secret)),
+		WithOnRequest(func(r *PreparedRequest) { observed = r.Header.Get("X-Signature") }))
+	if _, err := client.Get("Food", map[string]string{"name": "Miso ramen"}); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if _, err := client.Post("Food", []byte(`{"name":"Udon"}`)); err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	if observed == "" {
+		t.Error("Expected observers to see the signed request")
+	}
+
+	// Requests outside the REST API are signed too.
+	if err := client.Warmup(context.Background()); err != nil {
+		t.Fatalf("Warmup returned error: %v", err)
+	}
+	if _, err := client.authRequest(context.Background(), http.MethodGet, "user", "Bearer user-jwt", nil); err != nil {
+		t.Fatalf("authRequest returned error: %v", err)
+	}
+	if len(paths) != 4 || paths[2] != "/rest/v1/" || paths[3] != "/auth/v1/user" {
+		t.Errorf("Unexpected signed requests %v", paths)
+	}
+
+	// Prepared requests, and the curl commands built from them, are signed.
+	req, err := client.From("Food").Eq("id", 1).Prepare()
+	if err != nil {
+		t.Fatalf("Prepare returned error: %v", err)
+	}
+	if req.Header.Get("X-Signature") == "" || !strings.Contains(req.ToCurl(), "X-Signature: ") {
+		t.Errorf("Expected the prepared request to be signed, got %v", req.Header)
+	}
+}
+
+func TestVerifyHMACSignature(t *testing.T) {
+	const secret = "gateway-secret"
+	client := NewClient("https://example.supabase.co", "key", "token")
+	body := []byte(`{"name":"Udon"}`)
+	sign := func(at time.Time) *http.Request {
+		prepared, err := client.Prepare(http.MethodPost, "Food", nil, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		timestamp := strconv.FormatInt(at.Unix(), 10)
+		prepared.Header.Set(SignatureTimestampHeader, timestamp)
+		prepared.Header.Set("X-Signature", hmacSignature(secret, CanonicalRequest(timestamp, prepared.Method, prepared.URL, body)))
+		req, err := prepared.newRequest(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	if err := VerifyHMACSignature(sign(time.Now()), body, "X-Signature", secret); err != nil {
+		t.Errorf("Expected fresh signature to verify, got %v", err)
+	}
+	if err := VerifyHMACSignature(sign(time.Now()), body, "X-Signature", "other"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for wrong secret, got %v", err)
+	}
+	if err := VerifyHMACSignature(sign(time.Now()), []byte(`{}`), "X-Signature", secret); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for modified body, got %v", err)
+	}
+	if err := VerifyHMACSignature(sign(time.Now().Add(-SignatureWindow-time.Minute)), body, "X-Signature", secret); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for replayed request, got %v", err)
+	}
+	replayed := sign(time.Now())
+	replayed.Header.Set(SignatureTimestampHeader, strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+	if err := VerifyHMACSignature(replayed, body, "X-Signature", secret); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for altered timestamp, got %v", err)
+	}
+}
+
+func TestCanonicalRequest(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "key", "token")
+	req, err := client.Prepare(http.MethodPost, "Food", map[string][]string{"columns": {"id"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "1767225600\nPOST\n/rest/v1/Food?columns=id\ne3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	if got := CanonicalRequest("1767225600", req.Method, req.URL, req.Body); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,62 @@
+package supabase
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// WithSoftDelete marks rows of table as deleted by setting column, a nullable
+// timestamp such as deleted_at, instead of removing them:
+//
+//	client := supabase.NewClient(url, key, token, supabase.WithSoftDelete("notes", "deleted_at"))
+//	client.Delete("notes", "id", "7") // PATCH notes?id=eq.7&deleted_at=is.null
+//
+// Reads of the table only return rows where column is null, unless the query
+// filters on column itself, and deletes become a PATCH setting column to the
+// current time on rows not deleted yet. Embedded resources are not filtered.
+// Use WithDeleted to read deleted rows or purge them.
+func WithSoftDelete(table, column string) Option {
+	return func(c *Client) {
+		if c.softDeletes == nil {
+			c.softDeletes = map[string]string{}
+		}
+		c.softDeletes[table] = column
+	}
+}
+
+// WithDeleted returns a clone of the client that ignores WithSoftDelete:
+// reads include deleted rows and deletes remove rows permanently.
+func (c *Client) WithDeleted() *Client {
+	cp := c.clone()
+	cp.withDeleted = true
+	return cp
+}
+
+// softDelete rewrites a request to a soft-deleted table: reads get an
+// is.null filter on the delete column and deletes become updates of it.
+func (c *Client) softDelete(method, endpoint string, query url.Values, body []byte) (string, url.Values, []byte) {
+	if c.withDeleted || c.rootPath {
+		return method, query, body
+	}
+	table, rawQuery, _ := strings.Cut(endpoint, "?")
+	column, ok := c.softDeletes[table]
+	if !ok || (method != http.MethodGet && method != http.MethodHead && method != http.MethodDelete) {
+		return method, query, body
+	}
+	extra, _ := url.ParseQuery(rawQuery)
+	if method != http.MethodDelete && (query.Has(column) || extra.Has(column)) {
+		return method, query, body
+	}
+	query = cloneValues(query)
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Add(column, "is.null")
+	if method == http.MethodDelete {
+		method = http.MethodPatch
+		body, _ = c.getCodec().Marshal(map[string]string{column: time.Now().UTC().Format(time.RFC3339Nano)})
+	}
+	return method, query, body
+}
@@ -0,0 +1,60 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSoftDelete(t *testing.T) {
+	type request struct {
+		method, query string
+		body          []byte
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, request{r.Method, r.URL.RawQuery, body})
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token", WithSoftDelete("notes", "deleted_at"))
+
+	client.Get("notes", map[string]string{"id": "7"})
+	client.From("notes").Select("id").Execute(context.Background())
+	client.Get("notes?deleted_at=not.is.null")
+	client.Get("other")
+	client.Delete("notes", "id", "7")
+	client.WithDeleted().Get("notes")
+	client.WithDeleted().Delete("notes", "id", "7")
+
+	want := []request{
+		{http.MethodGet, "deleted_at=is.null&id=eq.7", nil},
+		{http.MethodGet, "deleted_at=is.null&select=id", nil},
+		{http.MethodGet, "deleted_at=not.is.null", nil},
+		{http.MethodGet, "", nil},
+		{http.MethodPatch, "deleted_at=is.null&id=eq.7", nil},
+		{http.MethodGet, "", nil},
+		{http.MethodDelete, "id=eq.7", nil},
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("Expected %d requests, got %+v", len(want), requests)
+	}
+	for i, w := range want {
+		if requests[i].method != w.method || requests[i].query != w.query {
+			t.Errorf("Request %d: expected %s ?%s, got %s ?%s", i, w.method, w.query, requests[i].method, requests[i].query)
+		}
+	}
+
+	var patch map[string]string
+	if err := json.Unmarshal(requests[4].body, &patch); err != nil {
+		t.Fatalf("Expected JSON patch body, got %s", requests[4].body)
+	}
+	if deleted, err := time.Parse(time.RFC3339Nano, patch["deleted_at"]); err != nil || time.Since(deleted) > time.Minute {
+		t.Errorf("Expected current timestamp for deleted_at, got %q", patch["deleted_at"])
+	}
+}
@@ -1,11 +1,16 @@
 package supabase
 
 import (
-	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 // Client represents the Supabase client
@@ -13,17 +18,74 @@ type Client struct {
 	BaseUrl string
 	ApiKey  string
 	Token   string
+
+	httpClient          *http.Client
+	maxResponseSize     int64
+	codec               Codec
+	dialContext         DialContextFunc
+	resolver            *net.Resolver
+	writeQueue          *WriteQueue
+	retryPolicy         *RetryPolicy
+	methodRetryPolicies map[string]*RetryPolicy
+	header              http.Header
+	hedgeDelay          time.Duration
+	enums               map[string]map[string]*Enum
+	skipQueryValidation bool
+	dryRun              bool
+	local               bool
+	proxy               *url.URL
+	timeout             time.Duration
+	schema              string
+	faults              *FaultConfig
+	onRequest           []func(*PreparedRequest)
+	signers             []func(*PreparedRequest) error
+	sessionCookie       *http.Cookie
+	serviceRoleKey      string
+	elevated            bool
+	asUser              bool
+	jwtSecret           string
+	rootPath            bool
+	serviceURLs         map[Service]string
+	servicePaths        map[Service]string
+	softDeletes         map[string]string
+	withDeleted         bool
+	tenantColumn        string
+	tenantValue         any
+	selectColumns       string
+
+	// restBase caches the parsed REST API URL for restBaseFor, the BaseUrl
+	// it was computed from.
+	restBase    *url.URL
+	restBaseFor string
 }
 
-const restApiPath = "/rest/v1"
+const (
+	restApiPath = "/rest/v1"
+	authApiPath = "/auth/v1"
+)
 
-// NewClient creates a new Supabase client
-func NewClient(baseUrl, apiKey, token string) *Client {
-	return &Client{
+// NewClient creates a new Supabase client. Options are applied in order.
+func NewClient(baseUrl, apiKey, token string, opts ...Option) *Client {
+	c := &Client{
 		BaseUrl: baseUrl,
 		ApiKey:  apiKey,
 		Token:   token,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Transport: c.newTransport(), Timeout: c.timeout}
+	}
+	if c.faults != nil {
+		hc := *c.httpClient
+		hc.Transport = &FaultTransport{Base: hc.Transport, Config: *c.faults}
+		c.httpClient = &hc
+	}
+	if base, err := url.Parse(c.ServiceURL(ServiceREST)); err == nil {
+		c.restBase, c.restBaseFor = base, c.BaseUrl
+	}
+	return c
 }
 
 // Get performs a GET request to the Supabase REST API. Requires table name and query param.
@@ -35,22 +97,73 @@ func (c *Client) Get(endpoint string, queryParams ...map[string]string) ([]byte,
 	return c.doRequest("GET", endpoint, params, nil)
 }
 
+// GetWhere performs a GET request for the rows of table matching all filters.
+// Unlike Get, which treats every query param as an equality, it takes any
+// filter:
+//
+//	body, err := client.GetWhere(ctx, "Food", supabase.Gte("rating", 4), supabase.Neq("status", "sold out"))
+func (c *Client) GetWhere(ctx context.Context, table string, filters ...Filter) ([]byte, error) {
+	query := url.Values{}
+	for _, f := range filters {
+		key, value := f.param()
+		query.Add(key, value)
+	}
+	resp, err := c.send(ctx, http.MethodGet, table, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
 // Post performs a POST request to the Supabase REST API. Requires table name, and request data.
 func (c *Client) Post(endpoint string, data []byte) ([]byte, error) {
-	return c.doRequest("POST", endpoint, nil, bytes.NewBuffer(data))
+	return c.doRequest("POST", endpoint, nil, data)
 }
 
 // Put performs a PUT request to the Supabase REST API. Requires table name, primary key, primary key value, and request data.
+// PostgREST replaces or inserts the single row identified by the primary key, so data must be one JSON object with every
+// column, including primaryKeyName set to primaryKeyValue; otherwise an error wrapping ErrPutMismatch is returned. Use
+// WithRepresentation to get the written row back.
 func (c *Client) Put(endpoint string, primaryKeyName string, primaryKeyValue string, data []byte) ([]byte, error) {
+	if err := checkPutBody(primaryKeyName, primaryKeyValue, data); err != nil {
+		return nil, err
+	}
 	query := map[string]string{
 		primaryKeyName: primaryKeyValue,
 	}
-	return c.doRequest("PUT", endpoint, query, bytes.NewBuffer(data))
+	body, err := c.doRequest("PUT", endpoint, query, data)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Code == "PGRST115" {
+		return nil, fmt.Errorf("%w: %v", ErrPutMismatch, err)
+	}
+	return body, err
+}
+
+// checkPutBody checks that a PUT body is a single object whose primary key
+// matches the filter, as PostgREST requires.
+func checkPutBody(primaryKeyName, primaryKeyValue string, data []byte) error {
+	var row map[string]json.RawMessage
+	if err := json.Unmarshal(data, &row); err != nil {
+		return fmt.Errorf("%w: body must be a single JSON object", ErrPutMismatch)
+	}
+	raw, ok := row[primaryKeyName]
+	if !ok {
+		return fmt.Errorf("%w: body is missing %q", ErrPutMismatch, primaryKeyName)
+	}
+	value := string(raw)
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		value = s
+	}
+	if value != primaryKeyValue {
+		return fmt.Errorf("%w: body has %s=%s, filter has %s", ErrPutMismatch, primaryKeyName, raw, primaryKeyValue)
+	}
+	return nil
 }
 
 // Patch performs a PATCH request to the Supabase REST API. Requires table name, query parameters, and request data.
 func (c *Client) Patch(endpoint string, queryParams map[string]string, data []byte) ([]byte, error) {
-	return c.doRequest("PATCH", endpoint, queryParams, bytes.NewBuffer(data))
+	return c.doRequest("PATCH", endpoint, queryParams, data)
 }
 
 // Delete performs a DELETE request to the Supabase REST API. Requires table name, primary key, and primary key value.
@@ -61,6 +174,91 @@ func (c *Client) Delete(endpoint string, primaryKeyName string, primaryKeyValue
 	return c.doRequest("DELETE", endpoint, query, nil)
 }
 
+// clone returns a copy of the client that can be modified without affecting
+// the original. The HTTP client and its connection pool are shared.
+func (c *Client) clone() *Client {
+	cp := *c
+	cp.header = c.header.Clone()
+	return &cp
+}
+
+// withHeader returns a clone of the client that sends the header on every request.
+func (c *Client) withHeader(key, value string) *Client {
+	cp := c.clone()
+	if cp.header == nil {
+		cp.header = http.Header{}
+	}
+	cp.header.Set(key, value)
+	return cp
+}
+
+// withPrefer returns a clone of the client that adds preference to the Prefer
+// header, replacing an earlier preference with the same name.
+func (c *Client) withPrefer(preference string) *Client {
+	name, _, _ := strings.Cut(preference, "=")
+	var kept []string
+	for _, existing := range strings.Split(c.header.Get("Prefer"), ",") {
+		existing = strings.TrimSpace(existing)
+		if existing == "" || strings.HasPrefix(existing, name+"=") || existing == name {
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	return c.withHeader("Prefer", strings.Join(append(kept, preference), ", "))
+}
+
+// WithRepresentation returns a copy of the client that asks PostgREST to
+// return the written rows, so Post, Put, Patch, and Delete return them
+// instead of an empty body.
+func (c *Client) WithRepresentation() *Client {
+	return c.withPrefer("return=representation")
+}
+
+// WithSelect returns a copy of the client that asks for only columns of the
+// rows it reads or writes, adding select= to requests that do not set it
+// themselves. Writes return the selected columns of the written rows:
+//
+//	body, err := client.WithSelect("id", "food_name").Get("Food", map[string]string{"rating": "5"})
+//	// GET Food?rating=eq.5&select=id,food_name
+//	row, err := client.WithSelect("id").Post("Food", []byte(`{"food_name":"Udon"}`))
+//	// [{"id":3}]
+//
+// Columns may be anything Select accepts, including embedded resources.
+func (c *Client) WithSelect(columns ...Column) *Client {
+	cp := c.withPrefer("return=representation")
+	cp.selectColumns = joinColumns(columns)
+	return cp
+}
+
+// WithAccept returns a copy of the client that requests responses as
+// mediaType, for PostgREST's built-in media types (text/csv,
+// application/geo+json) and custom media type handlers such as text/xml. The
+// body is returned as sent; Response.ContentType reports what was received:
+//
+//	resp, err := client.WithAccept("text/xml").From("feeds").Eq("id", 1).Execute(ctx)
+func (c *Client) WithAccept(mediaType string) *Client {
+	return c.withHeader("Accept", mediaType)
+}
+
+// WithToken returns a copy of the client that sends token as the
+// Authorization header, sharing the HTTP client and options. The token is sent
+// as given, so it should include the "Bearer " prefix, as incoming
+// Authorization headers do.
+func (c *Client) WithToken(token string) *Client {
+	cp := c.clone()
+	cp.Token = token
+	return cp
+}
+
+// client returns the HTTP client used for requests. Clients built as struct
+// literals rather than through NewClient fall back to http.DefaultClient.
+func (c *Client) client() *http.Client {
+	if c.httpClient != nil {
+		return c.httpClient
+	}
+	return http.DefaultClient
+}
+
 // formatQueryParams formats query parameters for Supabase compatibility
 func formatQueryParams(params map[string]string) map[string]string {
 	formattedParams := make(map[string]string)
@@ -70,35 +268,86 @@ func formatQueryParams(params map[string]string) map[string]string {
 	return formattedParams
 }
 
-// doRequest performs the actual HTTP request. Requires API key, and Token for headers
-func (c *Client) doRequest(method, endpoint string, queryParams map[string]string, body io.Reader) ([]byte, error) {
-	urlStr := fmt.Sprintf("%s%s/%s", c.BaseUrl, restApiPath, endpoint)
-	if len(queryParams) > 0 {
-		urlObj, err := url.Parse(urlStr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse URL: %v", err)
-		}
-		q := urlObj.Query()
-		for key, value := range formatQueryParams(queryParams) {
-			q.Add(key, value)
+// Execute performs a request against the Supabase REST API and returns the
+// full response, including status, headers, and server timings. Query values
+// are sent as given, so PostgREST operators must already be applied
+// (e.g. "rating": {"gte.4"}).
+func (c *Client) Execute(method, endpoint string, query url.Values, body []byte) (*Response, error) {
+	return c.send(context.Background(), method, endpoint, query, body)
+}
+
+// Do performs a request against any path of the Supabase project, relative to
+// BaseUrl (e.g. "/functions/v1/hello" or "/rest/v1/Food"), for endpoints
+// without a dedicated method. It uses the client's authentication headers,
+// retry policy, and error handling; header adds to or overrides the default
+// headers, and query is sent as given without validation:
+//
+//	resp, err := client.Do(ctx, http.MethodPost, "/functions/v1/hello", nil, nil, []byte(`{"name":"Ada"}`))
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, header http.Header, body []byte) (*Response, error) {
+	// rootPath makes requestURL resolve endpoints against BaseUrl rather
+	// than the REST API.
+	cp := c.clone()
+	cp.rootPath = true
+	return cp.executeWithRetry(ctx, method, strings.TrimPrefix(path, "/"), query, header, body)
+}
+
+// doRequest performs a request with eq. filters built from queryParams and returns the response body.
+func (c *Client) doRequest(method, endpoint string, queryParams map[string]string, body []byte) ([]byte, error) {
+	query := url.Values{}
+	for key, value := range formatQueryParams(queryParams) {
+		query.Add(key, value)
+	}
+	resp, err := c.send(context.Background(), method, endpoint, query, body)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// send performs a request on behalf of a public method. Writes that fail
+// transiently are recorded in the write queue when one is configured.
+func (c *Client) send(ctx context.Context, method, endpoint string, query url.Values, body []byte) (*Response, error) {
+	method, query, body = c.softDelete(method, endpoint, query, body)
+	if c.selectColumns != "" && !query.Has("select") && !strings.Contains(endpoint, "select=") {
+		query = cloneValues(query)
+		if query == nil {
+			query = url.Values{}
 		}
-		urlObj.RawQuery = q.Encode()
-		urlStr = urlObj.String()
+		query.Set("select", c.selectColumns)
+	}
+	if err := c.validate(endpoint, query, body); err != nil {
+		return nil, err
+	}
+	if c.writeQueue != nil && isWrite(method) {
+		return c.sendQueued(ctx, method, endpoint, query, body)
 	}
+	return c.executeWithRetry(ctx, method, endpoint, query, nil, body)
+}
 
-	req, err := http.NewRequest(method, urlStr, body)
+// execute performs the actual HTTP request. Requires API key, and Token for headers
+func (c *Client) execute(ctx context.Context, method, endpoint string, query url.Values, header http.Header, body []byte) (*Response, error) {
+	prepared, err := c.prepare(method, endpoint, query, header, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, err
+	}
+	if err := c.sign(prepared); err != nil {
+		return nil, err
+	}
+	for _, fn := range c.onRequest {
+		fn(prepared)
+	}
+	if c.dryRun {
+		return nil, &DryRunError{Request: prepared}
 	}
 
-	req.Header.Set("apikey", c.ApiKey)
-	req.Header.Set("Authorization", c.Token)
-	req.Header.Set("Content-Type", "application/json")
+	req, err := prepared.newRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.client().Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %v", err)
+		return nil, fmt.Errorf("failed to perform request: %w", err)
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -108,9 +357,79 @@ func (c *Client) doRequest(method, endpoint string, queryParams map[string]strin
 	}(resp.Body)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("error: %s", string(body))
+		// An oversized error body is cut short rather than dropped, so the
+		// APIError keeps its status and the start of the message.
+		body, _ := c.readBody(resp.Body)
+		apiErr := newAPIError(resp.StatusCode, body)
+		if c.rootPath {
+			return nil, apiErr
+		}
+		return nil, rowLevelSecurityError(method, endpoint, apiErr)
 	}
 
-	return io.ReadAll(resp.Body)
+	data, err := c.readBody(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return newResponse(resp, data), nil
+}
+
+// requestURL builds the URL for a REST endpoint. The endpoint may carry its
+// own query string, which is merged with query.
+func (c *Client) requestURL(endpoint string, query url.Values) (*url.URL, error) {
+	path, rawQuery, _ := strings.Cut(endpoint, "?")
+	if strings.Contains(path, "%") {
+		unescaped, err := url.PathUnescape(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse URL: %v", err)
+		}
+		path = unescaped
+	}
+
+	base := c.restBase
+	if base == nil || c.restBaseFor != c.BaseUrl || c.rootPath {
+		// BaseUrl was set or changed after NewClient; parse it without
+		// caching so concurrent requests never race on the cache.
+		baseURL := c.ServiceURL(ServiceREST)
+		if c.rootPath {
+			baseURL, path = c.resolvePath(path)
+		}
+		var err error
+		if base, err = url.Parse(baseURL); err != nil {
+			return nil, fmt.Errorf("failed to parse URL: %v", err)
+		}
+	}
+
+	u := *base
+	u.Path = strings.TrimSuffix(base.Path, "/") + "/" + path
+	u.RawPath = ""
+	u.RawQuery = rawQuery
+	if len(query) > 0 {
+		q, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse URL: %v", err)
+		}
+		for key, values := range query {
+			q[key] = append(q[key], values...)
+		}
+		u.RawQuery = q.Encode()
+	}
+	return &u, nil
+}
+
+// readBody reads a response body, enforcing the configured maximum size. On
+// error it also returns what was read, cut to the maximum size, so error
+// responses keep their details.
+func (c *Client) readBody(body io.Reader) ([]byte, error) {
+	if c.maxResponseSize <= 0 {
+		return io.ReadAll(body)
+	}
+	data, err := io.ReadAll(io.LimitReader(body, c.maxResponseSize+1))
+	if err != nil {
+		return data, err
+	}
+	if int64(len(data)) > c.maxResponseSize {
+		return data[:c.maxResponseSize], ErrResponseTooLarge
+	}
+	return data, nil
 }
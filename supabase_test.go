@@ -1,6 +1,13 @@
 package supabase
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
 
 func TestNewClient(t *testing.T) {
 	baseUrl := "https://example.supabase.co"
@@ -19,3 +26,164 @@ func TestNewClient(t *testing.T) {
 		t.Errorf("Expected Token to be %s, got %s", token, client.Token)
 	}
 }
+
+func TestRequestURL(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "your_api_key", "your_token")
+
+	tests := []struct {
+		endpoint string
+		query    url.Values
+		want     string
+	}{
+		{"Food", nil, "https://example.supabase.co/rest/v1/Food"},
+		{"Food", url.Values{"id": {"eq.1"}}, "https://example.supabase.co/rest/v1/Food?id=eq.1"},
+		{"Food?select=id", nil, "https://example.supabase.co/rest/v1/Food?select=id"},
+		{"Food?select=id", url.Values{"id": {"eq.1"}}, "https://example.supabase.co/rest/v1/Food?id=eq.1&select=id"},
+		{"rpc/top_rated", nil, "https://example.supabase.co/rest/v1/rpc/top_rated"},
+		{"Food%20Log", nil, "https://example.supabase.co/rest/v1/Food%20Log"},
+	}
+	for _, tt := range tests {
+		got, err := client.requestURL(tt.endpoint, tt.query)
+		if err != nil {
+			t.Errorf("requestURL(%q) returned error: %v", tt.endpoint, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("requestURL(%q) = %s, want %s", tt.endpoint, got, tt.want)
+		}
+	}
+
+	// BaseUrl changes after construction are honoured.
+	client.BaseUrl = "https://other.supabase.co"
+	if got, _ := client.requestURL("Food", nil); got.Host != "other.supabase.co" {
+		t.Errorf("Expected updated BaseUrl to be used, got %s", got)
+	}
+}
+
+func BenchmarkRequestURL(b *testing.B) {
+	client := NewClient("https://example.supabase.co", "your_api_key", "your_token")
+	query := url.Values{"id": {"eq.1"}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.requestURL("Food", query); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestWithPrefer(t *testing.T) {
+	client := NewClient("https://example.supabase.co", "key", "token")
+	c := client.withPrefer("count=exact").withPrefer("return=representation").withPrefer("count=planned")
+	if got, want := c.header.Get("Prefer"), "return=representation, count=planned"; got != want {
+		t.Errorf("Expected Prefer %q, got %q", want, got)
+	}
+	if client.header.Get("Prefer") != "" {
+		t.Error("Expected the original client to be unchanged")
+	}
+}
+
+func TestPut(t *testing.T) {
+	var gotPrefer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPrefer = r.Header.Get("Prefer")
+		if r.URL.Query().Get("id") == "eq.9" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":"PGRST115","message":"Payload values do not match URL in primary key column(s)"}`))
+			return
+		}
+		w.Write([]byte(`[{"id":1,"name":"a"}]`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token")
+
+	for _, body := range []string{`[{"id":1}]`, `{"name":"a"}`, `{"id":2}`, `{"id":"1x"}`} {
+		if _, err := client.Put("Food", "id", "1", []byte(body)); !errors.Is(err, ErrPutMismatch) {
+			t.Errorf("Expected ErrPutMismatch for %s, got %v", body, err)
+		}
+	}
+	if _, err := client.Put("Food", "id", "9", []byte(`{"id":9}`)); !errors.Is(err, ErrPutMismatch) {
+		t.Errorf("Expected ErrPutMismatch for PGRST115, got %v", err)
+	}
+
+	body, err := client.WithRepresentation().Put("Food", "id", "1", []byte(`{"id":1,"name":"a"}`))
+	if err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	if gotPrefer != "return=representation" || string(body) != `[{"id":1,"name":"a"}]` {
+		t.Errorf("Expected representation, got Prefer %q and body %s", gotPrefer, body)
+	}
+	if _, err := client.Put("Food", "slug", "a b", []byte(`{"slug":"a b"}`)); err != nil {
+		t.Errorf("Expected string key to match, got %v", err)
+	}
+}
+
+func TestDo(t *testing.T) {
+	var gotPath, gotAuth, gotCustom string
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		gotPath, gotAuth, gotCustom = r.URL.Path, r.Header.Get("Authorization"), r.Header.Get("X-Custom")
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"bad input"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "key", "Bearer token", WithRetryPolicy(RetryPolicy{MaxAttempts: 2}), WithSchema("api"))
+	resp, err := client.Do(context.Background(), http.MethodGet, "/functions/v1/hello", nil, http.Header{"X-Custom": {"1"}}, nil)
+	if err != nil {
+		t.Fatalf("Do returned error: %v", err)
+	}
+	if gotPath != "/functions/v1/hello" || gotAuth != "Bearer token" || gotCustom != "1" || string(resp.Body) != `{"ok":true}` {
+		t.Errorf("Unexpected request %s auth %q custom %q body %s", gotPath, gotAuth, gotCustom, resp.Body)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected the transient failure to be retried, got %d attempts", attempts)
+	}
+
+	_, err = client.Do(context.Background(), http.MethodGet, "storage/v1/bucket", url.Values{"fail": {"1"}}, nil, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected *APIError, got %v", err)
+	}
+}
+
+func TestWithSelect(t *testing.T) {
+	type request struct {
+		method, query, prefer string
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, request{r.Method, r.URL.Query().Encode(), r.Header.Get("Prefer")})
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token").WithSelect("id", "food_name")
+
+	client.Get("Food", map[string]string{"rating": "5"})
+	client.Post("Food", []byte(`{"food_name":"Udon"}`))
+	client.Patch("Food", map[string]string{"id": "1"}, []byte(`{"rating":4}`))
+	client.From("Food").Select("id").Execute(context.Background())
+
+	want := []request{
+		{http.MethodGet, "rating=eq.5&select=id%2Cfood_name", "return=representation"},
+		{http.MethodPost, "select=id%2Cfood_name", "return=representation"},
+		{http.MethodPatch, "id=eq.1&select=id%2Cfood_name", "return=representation"},
+		{http.MethodGet, "select=id", "return=representation"},
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("Expected %d requests, got %+v", len(want), requests)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("Request %d: expected %+v, got %+v", i, want[i], requests[i])
+		}
+	}
+}
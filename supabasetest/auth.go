@@ -0,0 +1,340 @@
+package supabasetest
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// JWTSecret signs the HS256 access tokens issued by the fake auth endpoints.
+const JWTSecret = "supabasetest-jwt-secret"
+
+// OTPCode is the one-time code accepted by the fake /verify endpoint for any
+// address an OTP was sent to.
+const OTPCode = "123456"
+
+const authApiPath = "/auth/v1/"
+
+// User is a user known to the fake auth endpoints.
+type User struct {
+	ID           string         `json:"id"`
+	Email        string         `json:"email,omitempty"`
+	Phone        string         `json:"phone,omitempty"`
+	Password     string         `json:"-"`
+	Unconfirmed  bool           `json:"-"`
+	Role         string         `json:"role"`
+	AppMetadata  map[string]any `json:"app_metadata"`
+	UserMetadata map[string]any `json:"user_metadata"`
+}
+
+// authFailure is a queued failure for an auth endpoint.
+type authFailure struct {
+	status    int
+	errorCode string
+	message   string
+}
+
+// authState holds the fake auth data. It is guarded by Server.mu.
+type authState struct {
+	users         map[string]*User // by ID
+	refreshTokens map[string]string
+	usedTokens    map[string]bool
+	otps          map[string]bool
+	failures      map[string][]authFailure
+	tokenTTL      time.Duration
+}
+
+func newAuthState() *authState {
+	return &authState{
+		users:         map[string]*User{},
+		refreshTokens: map[string]string{},
+		usedTokens:    map[string]bool{},
+		otps:          map[string]bool{},
+		failures:      map[string][]authFailure{},
+		tokenTTL:      time.Hour,
+	}
+}
+
+// AddUser registers a user with the fake auth endpoints and returns it with
+// defaults filled in.
+func (s *Server) AddUser(u User) User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.auth.addUser(u)
+}
+
+// SetTokenTTL sets the lifetime of issued access tokens, e.g. to exercise
+// refresh logic with short-lived sessions.
+func (s *Server) SetTokenTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth.tokenTTL = ttl
+}
+
+// FailAuth makes the next request to the auth endpoint (e.g. "token",
+// "signup", "user") fail with status and a GoTrue error code such as
+// "over_email_send_rate_limit". Failures queue up in order.
+func (s *Server) FailAuth(endpoint string, status int, errorCode, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth.failures[endpoint] = append(s.auth.failures[endpoint], authFailure{status, errorCode, message})
+}
+
+// AccessToken issues an access token for the user with the given email, for
+// tests that need an authenticated client without a sign-in flow.
+func (s *Server) AccessToken(email string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.auth.userByEmail(email)
+	if u == nil {
+		return "", fmt.Errorf("supabasetest: unknown user %q", email)
+	}
+	return s.auth.accessToken(u), nil
+}
+
+func (a *authState) addUser(u User) *User {
+	if u.ID == "" {
+		u.ID = newUUID()
+	}
+	if u.Role == "" {
+		u.Role = "authenticated"
+	}
+	if u.AppMetadata == nil {
+		u.AppMetadata = map[string]any{"provider": "email"}
+	}
+	if u.UserMetadata == nil {
+		u.UserMetadata = map[string]any{}
+	}
+	a.users[u.ID] = &u
+	return &u
+}
+
+func (a *authState) userByEmail(email string) *User {
+	for _, u := range a.users {
+		if email != "" && strings.EqualFold(u.Email, email) {
+			return u
+		}
+	}
+	return nil
+}
+
+func (a *authState) userByPhone(phone string) *User {
+	for _, u := range a.users {
+		if phone != "" && u.Phone == phone {
+			return u
+		}
+	}
+	return nil
+}
+
+// serveAuth handles requests under /auth/v1/.
+func (s *Server) serveAuth(w http.ResponseWriter, r *http.Request) {
+	endpoint := strings.TrimPrefix(r.URL.Path, authApiPath)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.auth
+
+	if queued := a.failures[endpoint]; len(queued) > 0 {
+		a.failures[endpoint] = queued[1:]
+		writeAuthError(w, queued[0].status, queued[0].errorCode, queued[0].message)
+		return
+	}
+
+	var body struct {
+		Email        string         `json:"email"`
+		Phone        string         `json:"phone"`
+		Password     string         `json:"password"`
+		RefreshToken string         `json:"refresh_token"`
+		Token        string         `json:"token"`
+		Type         string         `json:"type"`
+		Data         map[string]any `json:"data"`
+	}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeAuthError(w, http.StatusBadRequest, "bad_json", "Could not parse request body as JSON")
+			return
+		}
+	}
+
+	switch {
+	case endpoint == "token" && r.Method == http.MethodPost:
+		switch r.URL.Query().Get("grant_type") {
+		case "password":
+			u := a.userByEmail(body.Email)
+			if u == nil {
+				u = a.userByPhone(body.Phone)
+			}
+			if u == nil || u.Password != body.Password {
+				writeAuthError(w, http.StatusBadRequest, "invalid_credentials", "Invalid login credentials")
+				return
+			}
+			if u.Unconfirmed {
+				writeAuthError(w, http.StatusBadRequest, "email_not_confirmed", "Email not confirmed")
+				return
+			}
+			writeJSON(w, http.StatusOK, a.session(u))
+		case "refresh_token":
+			if a.usedTokens[body.RefreshToken] {
+				writeAuthError(w, http.StatusBadRequest, "refresh_token_already_used", "Invalid Refresh Token: Already Used")
+				return
+			}
+			userID, ok := a.refreshTokens[body.RefreshToken]
+			if !ok {
+				writeAuthError(w, http.StatusBadRequest, "refresh_token_not_found", "Invalid Refresh Token: Refresh Token Not Found")
+				return
+			}
+			delete(a.refreshTokens, body.RefreshToken)
+			a.usedTokens[body.RefreshToken] = true
+			writeJSON(w, http.StatusOK, a.session(a.users[userID]))
+		default:
+			writeAuthError(w, http.StatusBadRequest, "validation_failed", "unsupported grant_type")
+		}
+
+	case endpoint == "signup" && r.Method == http.MethodPost:
+		if a.userByEmail(body.Email) != nil || a.userByPhone(body.Phone) != nil {
+			writeAuthError(w, http.StatusUnprocessableEntity, "user_already_exists", "User already registered")
+			return
+		}
+		u := a.addUser(User{Email: body.Email, Phone: body.Phone, Password: body.Password, UserMetadata: body.Data})
+		writeJSON(w, http.StatusOK, a.session(u))
+
+	case endpoint == "otp" && r.Method == http.MethodPost:
+		a.otps[body.Email+body.Phone] = true
+		writeJSON(w, http.StatusOK, map[string]any{})
+
+	case endpoint == "verify" && r.Method == http.MethodPost:
+		address := body.Email + body.Phone
+		if !a.otps[address] || body.Token != OTPCode {
+			writeAuthError(w, http.StatusForbidden, "otp_expired", "Token has expired or is invalid")
+			return
+		}
+		delete(a.otps, address)
+		u := a.userByEmail(body.Email)
+		if u == nil {
+			u = a.userByPhone(body.Phone)
+		}
+		if u == nil {
+			u = a.addUser(User{Email: body.Email, Phone: body.Phone})
+		}
+		u.Unconfirmed = false
+		writeJSON(w, http.StatusOK, a.session(u))
+
+	case endpoint == "user" && r.Method == http.MethodGet:
+		u := a.userFromToken(r.Header.Get("Authorization"))
+		if u == nil {
+			writeAuthError(w, http.StatusUnauthorized, "bad_jwt", "invalid JWT")
+			return
+		}
+		writeJSON(w, http.StatusOK, u)
+
+	case endpoint == "logout" && r.Method == http.MethodPost:
+		u := a.userFromToken(r.Header.Get("Authorization"))
+		if u == nil {
+			writeAuthError(w, http.StatusUnauthorized, "bad_jwt", "invalid JWT")
+			return
+		}
+		for token, userID := range a.refreshTokens {
+			if userID == u.ID {
+				delete(a.refreshTokens, token)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeAuthError(w, http.StatusNotFound, "not_found", "unsupported auth endpoint "+endpoint)
+	}
+}
+
+// session issues a new access and refresh token pair for u.
+func (a *authState) session(u *User) map[string]any {
+	refreshToken := newUUID()
+	a.refreshTokens[refreshToken] = u.ID
+	return map[string]any{
+		"access_token":  a.accessToken(u),
+		"token_type":    "bearer",
+		"expires_in":    int(a.tokenTTL.Seconds()),
+		"expires_at":    time.Now().Add(a.tokenTTL).Unix(),
+		"refresh_token": refreshToken,
+		"user":          u,
+	}
+}
+
+// accessToken signs an HS256 access token for u with JWTSecret.
+func (a *authState) accessToken(u *User) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims, _ := json.Marshal(map[string]any{
+		"sub":           u.ID,
+		"email":         u.Email,
+		"phone":         u.Phone,
+		"role":          u.Role,
+		"aud":           "authenticated",
+		"exp":           time.Now().Add(a.tokenTTL).Unix(),
+		"iat":           time.Now().Unix(),
+		"app_metadata":  u.AppMetadata,
+		"user_metadata": u.UserMetadata,
+	})
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	mac := hmac.New(sha256.New, []byte(JWTSecret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// userFromToken verifies a bearer access token and returns its user.
+func (a *authState) userFromToken(authorization string) *User {
+	token := strings.TrimPrefix(authorization, "Bearer ")
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	mac := hmac.New(sha256.New, []byte(JWTSecret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil
+	}
+	var claims struct {
+		Sub string `json:"sub"`
+		Exp int64  `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || time.Now().Unix() >= claims.Exp {
+		return nil
+	}
+	return a.users[claims.Sub]
+}
+
+// writeAuthError writes a GoTrue-style error body.
+func writeAuthError(w http.ResponseWriter, status int, errorCode, message string) {
+	writeJSON(w, status, map[string]any{
+		"code":       status,
+		"error_code": errorCode,
+		"msg":        message,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	h := hex.EncodeToString(b)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
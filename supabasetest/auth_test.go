@@ -0,0 +1,94 @@
+package supabasetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func authRequest(t *testing.T, s *Server, method, path, token string, body any) (int, map[string]any) {
+	t.Helper()
+	data, _ := json.Marshal(body)
+	req, err := http.NewRequest(method, s.URL+authApiPath+path, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("apikey", APIKey)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var out map[string]any
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func TestAuthPasswordAndRefresh(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	user := server.AddUser(User{Email: "ramen@example.com", Password: "hunter22"})
+
+	status, out := authRequest(t, server, http.MethodPost, "token?grant_type=password", "", map[string]string{"email": "ramen@example.com", "password": "wrong"})
+	if status != http.StatusBadRequest || out["error_code"] != "invalid_credentials" {
+		t.Errorf("Expected invalid_credentials, got %d %v", status, out)
+	}
+
+	status, session := authRequest(t, server, http.MethodPost, "token?grant_type=password", "", map[string]string{"email": "ramen@example.com", "password": "hunter22"})
+	if status != http.StatusOK {
+		t.Fatalf("Expected sign-in to succeed, got %d %v", status, session)
+	}
+
+	status, out = authRequest(t, server, http.MethodGet, "user", session["access_token"].(string), nil)
+	if status != http.StatusOK || out["id"] != user.ID {
+		t.Errorf("Expected /user to return the signed-in user, got %d %v", status, out)
+	}
+
+	refresh := session["refresh_token"].(string)
+	status, rotated := authRequest(t, server, http.MethodPost, "token?grant_type=refresh_token", "", map[string]string{"refresh_token": refresh})
+	if status != http.StatusOK || rotated["refresh_token"] == refresh {
+		t.Errorf("Expected a rotated refresh token, got %d %v", status, rotated)
+	}
+	status, out = authRequest(t, server, http.MethodPost, "token?grant_type=refresh_token", "", map[string]string{"refresh_token": refresh})
+	if status != http.StatusBadRequest || out["error_code"] != "refresh_token_already_used" {
+		t.Errorf("Expected reuse to be detected, got %d %v", status, out)
+	}
+}
+
+func TestAuthSignupOTPAndFailures(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	status, _ := authRequest(t, server, http.MethodPost, "signup", "", map[string]string{"email": "udon@example.com", "password": "hunter22"})
+	if status != http.StatusOK {
+		t.Fatalf("Expected signup to succeed, got %d", status)
+	}
+	status, out := authRequest(t, server, http.MethodPost, "signup", "", map[string]string{"email": "udon@example.com", "password": "hunter22"})
+	if status != http.StatusUnprocessableEntity || out["error_code"] != "user_already_exists" {
+		t.Errorf("Expected user_already_exists, got %d %v", status, out)
+	}
+
+	authRequest(t, server, http.MethodPost, "otp", "", map[string]string{"phone": "+15555550100"})
+	status, session := authRequest(t, server, http.MethodPost, "verify", "", map[string]string{"phone": "+15555550100", "token": OTPCode, "type": "sms"})
+	if status != http.StatusOK || session["access_token"] == nil {
+		t.Errorf("Expected OTP verification to return a session, got %d %v", status, session)
+	}
+
+	server.FailAuth("signup", http.StatusTooManyRequests, "over_email_send_rate_limit", "email rate limit exceeded")
+	status, out = authRequest(t, server, http.MethodPost, "signup", "", map[string]string{"email": "soba@example.com", "password": "hunter22"})
+	if status != http.StatusTooManyRequests || out["error_code"] != "over_email_send_rate_limit" {
+		t.Errorf("Expected queued failure, got %d %v", status, out)
+	}
+
+	token, err := server.AccessToken("udon@example.com")
+	if err != nil {
+		t.Fatalf("AccessToken returned error: %v", err)
+	}
+	if status, _ := authRequest(t, server, http.MethodGet, "user", token, nil); status != http.StatusOK {
+		t.Errorf("Expected issued access token to be accepted, got %d", status)
+	}
+}
@@ -0,0 +1,187 @@
+package supabasetest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// filter is a parsed horizontal filter such as rating=not.gte.4.
+type filter struct {
+	column  string
+	op      string
+	operand string
+	negate  bool
+}
+
+// parseFilter parses a query parameter into a filter.
+func parseFilter(column, value string) (filter, error) {
+	f := filter{column: column}
+	if rest, ok := strings.CutPrefix(value, "not."); ok {
+		f.negate = true
+		value = rest
+	}
+	op, operand, ok := strings.Cut(value, ".")
+	if !ok {
+		return f, fmt.Errorf("invalid filter %s=%s", column, value)
+	}
+	switch op {
+	case "eq", "neq", "gt", "gte", "lt", "lte", "like", "ilike", "in", "is":
+	default:
+		return f, fmt.Errorf("unsupported operator %q in %s=%s", op, column, value)
+	}
+	f.op, f.operand = op, operand
+	return f, nil
+}
+
+// matchRows returns the rows that satisfy all filters, in order.
+func matchRows(rows []Row, filters []filter) []Row {
+	var matched []Row
+	for _, row := range rows {
+		if matchesAll(row, filters) {
+			matched = append(matched, row)
+		}
+	}
+	return matched
+}
+
+func matchesAll(row Row, filters []filter) bool {
+	for _, f := range filters {
+		if f.matches(row) == f.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether row satisfies the filter, ignoring negation. Like
+// SQL, comparisons against NULL never match.
+func (f filter) matches(row Row) bool {
+	value := row[f.column]
+	if f.op == "is" {
+		switch f.operand {
+		case "null":
+			return value == nil
+		case "true":
+			return value == true
+		case "false":
+			return value == false
+		case "unknown":
+			return value == nil
+		}
+		return false
+	}
+	if value == nil {
+		return false
+	}
+	switch f.op {
+	case "eq":
+		return compareValues(value, f.operand) == 0
+	case "neq":
+		return compareValues(value, f.operand) != 0
+	case "gt":
+		return compareValues(value, f.operand) > 0
+	case "gte":
+		return compareValues(value, f.operand) >= 0
+	case "lt":
+		return compareValues(value, f.operand) < 0
+	case "lte":
+		return compareValues(value, f.operand) <= 0
+	case "like":
+		return likePattern(f.operand, false).MatchString(toString(value))
+	case "ilike":
+		return likePattern(f.operand, true).MatchString(toString(value))
+	case "in":
+		for _, item := range parseList(f.operand) {
+			if compareValues(value, item) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareValues compares a stored value with a filter operand, numerically
+// when both are numbers and as strings otherwise.
+func compareValues(value any, operand string) int {
+	if n, ok := value.(float64); ok {
+		if m, err := strconv.ParseFloat(operand, 64); err == nil {
+			switch {
+			case n < m:
+				return -1
+			case n > m:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(toString(value), operand)
+}
+
+// toString renders a stored value the way PostgREST renders it in filters.
+func toString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(value)
+}
+
+// likePattern converts a LIKE pattern, where * and % match any run of
+// characters and _ matches one, into a regular expression.
+func likePattern(pattern string, fold bool) *regexp.Regexp {
+	var b strings.Builder
+	if fold {
+		b.WriteString("(?i)")
+	}
+	b.WriteString("^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*' || r == '%':
+			b.WriteString("(?s:.*)")
+		case r == '_':
+			b.WriteString("(?s:.)")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// parseList parses an in.(...) operand, honouring double-quoted items.
+func parseList(operand string) []string {
+	operand = strings.TrimSuffix(strings.TrimPrefix(operand, "("), ")")
+	var (
+		items   []string
+		current strings.Builder
+		quoted  bool
+	)
+	for i := 0; i < len(operand); i++ {
+		ch := operand[i]
+		switch {
+		case ch == '\\' && quoted && i+1 < len(operand):
+			i++
+			current.WriteByte(operand[i])
+		case ch == '"':
+			quoted = !quoted
+		case ch == ',' && !quoted:
+			items = append(items, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(ch)
+		}
+	}
+	return append(items, current.String())
+}
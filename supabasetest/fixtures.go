@@ -0,0 +1,73 @@
+package supabasetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// stub is a canned response for a method, table, and query.
+type stub struct {
+	method string
+	table  string
+	query  url.Values
+	status int
+	body   []byte
+}
+
+// LoadFixture reads a JSON fixture file, failing the test if it is missing or
+// is not valid JSON.
+func LoadFixture(tb testing.TB, path string) []byte {
+	tb.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("supabasetest: failed to read fixture: %v", err)
+	}
+	if !json.Valid(data) {
+		tb.Fatalf("supabasetest: fixture %s is not valid JSON", path)
+	}
+	return data
+}
+
+// Stub makes the server answer requests for table with the given method and
+// query (e.g. "id=eq.1&select=*") with status and body, bypassing the
+// in-memory tables. Query parameters are compared without regard to order.
+// Stubs are not consumed; a later stub for the same request takes precedence.
+func (s *Server) Stub(method, table, query string, status int, body []byte) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		panic("supabasetest: invalid stub query " + query)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stubs = append(s.stubs, stub{method, table, values, status, body})
+}
+
+// StubFixture stubs a 200 response for a GET of table with query, using the
+// JSON fixture at path as the body.
+func (s *Server) StubFixture(tb testing.TB, table, query, path string) {
+	tb.Helper()
+	s.Stub(http.MethodGet, table, query, http.StatusOK, LoadFixture(tb, path))
+}
+
+// findStub returns the most recent stub matching the request. The caller
+// must hold s.mu.
+func (s *Server) findStub(method, table string, query url.Values) *stub {
+	for i := len(s.stubs) - 1; i >= 0; i-- {
+		st := &s.stubs[i]
+		if st.method == method && st.table == table && sameQuery(st.query, query) {
+			return st
+		}
+	}
+	return nil
+}
+
+func sameQuery(a, b url.Values) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,34 @@
+package supabasetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestStubFixture(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.StubFixture(t, "Food", "rating=eq.5&select=id,food_name,rating", "testdata/top_rated_food.json")
+	server.Stub(http.MethodDelete, "Food", "id=eq.1", http.StatusForbidden, []byte(`{"code":"42501","message":"permission denied"}`))
+	client := server.Client("Bearer token")
+
+	resp, err := client.Execute(http.MethodGet, "Food", url.Values{"select": {"id,food_name,rating"}, "rating": {"eq.5"}}, nil)
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	var rows []Row
+	if err := json.Unmarshal(resp.Body, &rows); err != nil || len(rows) != 2 || rows[1]["food_name"] != "Pho" {
+		t.Errorf("Expected fixture rows, got %s (%v)", resp.Body, err)
+	}
+
+	if _, err := client.Delete("Food", "id", "1"); err == nil {
+		t.Error("Expected stubbed DELETE to fail")
+	}
+
+	// Requests that match no stub fall through to the in-memory tables.
+	if _, err := client.Get("Food", map[string]string{"rating": "4"}); err == nil {
+		t.Error("Expected unstubbed request to an unseeded table to fail")
+	}
+}
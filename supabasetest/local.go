@@ -0,0 +1,165 @@
+package supabasetest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtclarkjr/supabase-go-rest"
+)
+
+// StartEnv is the environment variable that lets LocalStack run
+// `supabase start` when no local stack is running.
+const StartEnv = "SUPABASE_TEST_START"
+
+// LocalStackInfo describes a running local Supabase stack.
+type LocalStackInfo struct {
+	URL            string
+	AnonKey        string
+	ServiceRoleKey string
+}
+
+// errNoLocalStack is returned by DetectLocalStack when no stack is found.
+var errNoLocalStack = errors.New("supabasetest: no local Supabase stack found")
+
+// healthPaths are polled by WaitHealthy, one per service.
+var healthPaths = map[string]string{
+	"rest":    "/rest/v1/",
+	"auth":    "/auth/v1/health",
+	"storage": "/storage/v1/status",
+}
+
+// LocalStack returns a client for a local Supabase stack, authenticated with
+// the anon key, once its REST, auth, and storage services report healthy. The
+// stack is taken from SUPABASE_URL and SUPABASE_ANON_KEY when set, and from
+// `supabase status` otherwise; with SUPABASE_TEST_START=1 it is booted with
+// `supabase start` if needed. The test is skipped when no stack is available
+// and fails if the stack does not become healthy within two minutes.
+func LocalStack(tb testing.TB, opts ...supabase.Option) *supabase.Client {
+	tb.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	info, err := DetectLocalStack(ctx)
+	if errors.Is(err, errNoLocalStack) {
+		tb.Skip("no local Supabase stack available; run `supabase start` or set " + StartEnv + "=1")
+	}
+	if err != nil {
+		tb.Fatalf("supabasetest: %v", err)
+	}
+	if err := WaitHealthy(ctx, info); err != nil {
+		tb.Fatalf("supabasetest: %v", err)
+	}
+	return supabase.NewClient(info.URL, info.AnonKey, "Bearer "+info.AnonKey, opts...)
+}
+
+// DetectLocalStack finds a local Supabase stack from the environment or the
+// Supabase CLI, starting one when StartEnv is set.
+func DetectLocalStack(ctx context.Context) (LocalStackInfo, error) {
+	if url := os.Getenv("SUPABASE_URL"); url != "" {
+		info := LocalStackInfo{
+			URL:            url,
+			AnonKey:        os.Getenv("SUPABASE_ANON_KEY"),
+			ServiceRoleKey: os.Getenv("SUPABASE_SERVICE_ROLE_KEY"),
+		}
+		if info.AnonKey == "" {
+			return info, errors.New("SUPABASE_URL is set but SUPABASE_ANON_KEY is not")
+		}
+		return info, nil
+	}
+
+	if _, err := exec.LookPath("supabase"); err != nil {
+		return LocalStackInfo{}, errNoLocalStack
+	}
+	info, err := cliStatus(ctx)
+	if err == nil {
+		return info, nil
+	}
+	if os.Getenv(StartEnv) == "" {
+		return LocalStackInfo{}, errNoLocalStack
+	}
+	if out, err := exec.CommandContext(ctx, "supabase", "start").CombinedOutput(); err != nil {
+		return LocalStackInfo{}, fmt.Errorf("supabase start failed: %v\n%s", err, out)
+	}
+	return cliStatus(ctx)
+}
+
+// cliStatus reads the stack details from `supabase status -o env`.
+func cliStatus(ctx context.Context) (LocalStackInfo, error) {
+	out, err := exec.CommandContext(ctx, "supabase", "status", "-o", "env").Output()
+	if err != nil {
+		return LocalStackInfo{}, fmt.Errorf("supabase status failed: %v", err)
+	}
+	env := parseStatusEnv(string(out))
+	info := LocalStackInfo{URL: env["API_URL"], AnonKey: env["ANON_KEY"], ServiceRoleKey: env["SERVICE_ROLE_KEY"]}
+	if info.URL == "" || info.AnonKey == "" {
+		return info, errors.New("supabase status did not report API_URL and ANON_KEY")
+	}
+	return info, nil
+}
+
+// parseStatusEnv parses KEY="value" lines as printed by `supabase status -o env`.
+func parseStatusEnv(output string) map[string]string {
+	env := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		env[key] = strings.Trim(value, `"`)
+	}
+	return env
+}
+
+// WaitHealthy polls the REST, auth, and storage services of the stack until
+// all respond with 200 or ctx is done.
+func WaitHealthy(ctx context.Context, info LocalStackInfo) error {
+	pending := map[string]string{}
+	for service, path := range healthPaths {
+		pending[service] = path
+	}
+	var lastErr error
+	for {
+		for service, path := range pending {
+			if lastErr = checkHealth(ctx, info, path); lastErr == nil {
+				delete(pending, service)
+			} else {
+				lastErr = fmt.Errorf("%s: %v", service, lastErr)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("local stack not healthy: %v", lastErr)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+func checkHealth(ctx context.Context, info LocalStackInfo, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(info.URL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("apikey", info.AnonKey)
+	req.Header.Set("Authorization", "Bearer "+info.AnonKey)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
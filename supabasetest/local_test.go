@@ -0,0 +1,60 @@
+package supabasetest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseStatusEnv(t *testing.T) {
+	env := parseStatusEnv("API_URL=\"http://127.0.0.1:54321\"\nANON_KEY=\"anon\"\nnot a pair\n")
+	if env["API_URL"] != "http://127.0.0.1:54321" || env["ANON_KEY"] != "anon" {
+		t.Errorf("Unexpected parsed env %v", env)
+	}
+}
+
+func TestWaitHealthy(t *testing.T) {
+	var storageChecks atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/v1/", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/auth/v1/health", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/storage/v1/status", func(w http.ResponseWriter, r *http.Request) {
+		// Storage comes up after a couple of polls.
+		if storageChecks.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := WaitHealthy(ctx, LocalStackInfo{URL: server.URL, AnonKey: "anon"}); err != nil {
+		t.Fatalf("WaitHealthy returned error: %v", err)
+	}
+	if storageChecks.Load() != 3 {
+		t.Errorf("Expected storage to be polled until healthy, got %d checks", storageChecks.Load())
+	}
+}
+
+func TestWaitHealthyTimeout(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := WaitHealthy(ctx, LocalStackInfo{URL: server.URL, AnonKey: "anon"}); err == nil {
+		t.Error("Expected WaitHealthy to time out")
+	}
+}
+
+func TestLocalStack(t *testing.T) {
+	// Skips unless a local stack is available.
+	client := LocalStack(t)
+	if _, err := client.Execute(http.MethodGet, "", nil, nil); err != nil {
+		t.Errorf("Expected the local REST API to respond, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package supabasetest
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jtclarkjr/supabase-go-rest"
+)
+
+func TestRecorder(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "food.json")
+
+	server := NewServer()
+	seedFood(server)
+
+	rec, err := NewRecorder(fixture, ModeRecord)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+	client := supabase.NewClient(server.URL, APIKey, "Bearer secret-user-token", supabase.WithHTTPClient(rec.Client()))
+	recorded, err := client.Get("Food", map[string]string{"id": "1"})
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if _, err := client.Post("Food", []byte(`{"id":5,"food_name":"Pho","access_token":"secret-user-token"}`)); err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	server.Close()
+
+	data, err := os.ReadFile(fixture)
+	if err != nil {
+		t.Fatalf("Fixture not written: %v", err)
+	}
+	for _, secret := range []string{"secret-user-token", APIKey} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Fixture contains secret %q:\n%s", secret, data)
+		}
+	}
+
+	// Replay against a different host with the server gone.
+	rec, err = NewRecorder(fixture, ModeReplay)
+	if err != nil {
+		t.Fatalf("NewRecorder returned error: %v", err)
+	}
+	client = supabase.NewClient("http://replay.invalid", "other-key", "", supabase.WithHTTPClient(rec.Client()))
+	replayed, err := client.Get("Food", map[string]string{"id": "1"})
+	if err != nil {
+		t.Fatalf("Replayed Get returned error: %v", err)
+	}
+	if string(replayed) != string(recorded) {
+		t.Errorf("Expected replayed body %s, got %s", recorded, replayed)
+	}
+	if _, err := client.Post("Food", []byte(`{"id":5,"food_name":"Pho","access_token":"another-token"}`)); err != nil {
+		t.Errorf("Expected scrubbed body to match, got %v", err)
+	}
+
+	_, err = client.Get("Food", map[string]string{"id": "2"})
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("Expected unrecorded request to fail, got %v", err)
+	}
+}
+
+func TestScrubURL(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://x.supabase.co/rest/v1/Food?apikey=abc&id=eq.1", nil)
+	if got := scrubURL(req.URL); got != "/rest/v1/Food?apikey=REDACTED&id=eq.1" {
+		t.Errorf("Unexpected scrubbed URL %s", got)
+	}
+}
@@ -0,0 +1,492 @@
+// Package supabasetest provides an in-memory fake of the Supabase REST API
+// (PostgREST) and auth API (GoTrue) for testing code that uses the supabase
+// client.
+//
+// It understands enough of PostgREST to back typical unit tests: horizontal
+// filters (eq, neq, gt, gte, lt, lte, like, ilike, in, is and their not.
+// forms), column selection, ordering, limit and offset, exact counts, and
+// insert, update, upsert, and delete with the return preference. Upserts
+// honour resolution=merge-duplicates and ignore-duplicates, matching rows on
+// the on_conflict columns or id, and inserts honour the columns parameter
+// and missing=default. The auth endpoints cover password and refresh token
+// grants, signup, OTP send and verify, user, and logout.
+package supabasetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jtclarkjr/supabase-go-rest"
+)
+
+// APIKey is the API key accepted by the fake server.
+const APIKey = "supabasetest-api-key"
+
+const restApiPath = "/rest/v1/"
+
+// Row is a table row keyed by column name. Values follow encoding/json
+// conventions: numbers are float64 and JSON null is nil.
+type Row = map[string]any
+
+// Server is an in-memory fake of the Supabase REST and auth APIs.
+type Server struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	tables map[string][]Row
+	auth   *authState
+	stubs  []stub
+}
+
+// NewServer starts a fake server with no tables or users. Call Close when done.
+func NewServer() *Server {
+	s := &Server{tables: map[string][]Row{}, auth: newAuthState()}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a supabase client for the fake server that sends token as
+// the Authorization header.
+func (s *Server) Client(token string, opts ...supabase.Option) *supabase.Client {
+	return supabase.NewClient(s.URL, APIKey, token, opts...)
+}
+
+// Seed creates table if needed and appends rows to it. Values are normalized
+// through JSON, so seeding an int stores a float64 as an insert would.
+// Seeding a table with no rows creates it empty; requests to tables that were
+// never seeded fail like requests to a missing relation.
+func (s *Server) Seed(table string, rows ...Row) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, row := range rows {
+		data, err := json.Marshal(row)
+		if err != nil {
+			panic(fmt.Sprintf("supabasetest: cannot seed %s: %v", table, err))
+		}
+		var normalized Row
+		_ = json.Unmarshal(data, &normalized)
+		s.tables[table] = append(s.tables[table], normalized)
+	}
+	if _, ok := s.tables[table]; !ok {
+		s.tables[table] = []Row{}
+	}
+}
+
+// Rows returns a copy of the rows currently stored in table.
+func (s *Server) Rows(table string) []Row {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rows := make([]Row, len(s.tables[table]))
+	for i, row := range s.tables[table] {
+		rows[i] = copyRow(row)
+	}
+	return rows
+}
+
+// reservedParams are query parameters that are not horizontal filters.
+var reservedParams = map[string]bool{
+	"select": true, "order": true, "limit": true, "offset": true,
+	"columns": true, "on_conflict": true,
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("apikey") != APIKey {
+		writeError(w, http.StatusUnauthorized, "PGRST301", "invalid API key")
+		return
+	}
+	if strings.HasPrefix(r.URL.Path, authApiPath) {
+		s.serveAuth(w, r)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, restApiPath) {
+		writeError(w, http.StatusNotFound, "PGRST125", "invalid path "+r.URL.Path)
+		return
+	}
+	table := strings.TrimPrefix(r.URL.Path, restApiPath)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if st := s.findStub(r.Method, table, r.URL.Query()); st != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(st.status)
+		w.Write(st.body)
+		return
+	}
+
+	rows, ok := s.tables[table]
+	if !ok {
+		writeError(w, http.StatusNotFound, "42P01", fmt.Sprintf("relation \"public.%s\" does not exist", table))
+		return
+	}
+
+	query := r.URL.Query()
+	var filters []filter
+	for key, values := range query {
+		if reservedParams[key] {
+			continue
+		}
+		for _, value := range values {
+			f, err := parseFilter(key, value)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "PGRST100", err.Error())
+				return
+			}
+			filters = append(filters, f)
+		}
+	}
+	prefer := parsePrefer(r.Header.Values("Prefer"))
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		matched := matchRows(rows, filters)
+		if err := sortRows(matched, query.Get("order")); err != nil {
+			writeError(w, http.StatusBadRequest, "PGRST100", err.Error())
+			return
+		}
+		total := len(matched)
+		matched, offset, err := paginate(matched, query.Get("offset"), query.Get("limit"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "PGRST100", err.Error())
+			return
+		}
+		w.Header().Set("Content-Range", contentRange(offset, len(matched), total, prefer["count"] == "exact"))
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		writeRows(w, http.StatusOK, matched, query.Get("select"))
+
+	case http.MethodPost:
+		var inserted []Row
+		if err := decodeRows(r, &inserted); err != nil {
+			writeError(w, http.StatusBadRequest, "PGRST102", err.Error())
+			return
+		}
+		inserted = limitColumns(inserted, query.Get("columns"), prefer["missing"] == "default")
+		rows, written := insertRows(rows, inserted, prefer["resolution"], query.Get("on_conflict"))
+		s.tables[table] = rows
+		s.respondWrite(w, http.StatusCreated, written, prefer, query.Get("select"))
+
+	case http.MethodPatch:
+		var patch Row
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			writeError(w, http.StatusBadRequest, "PGRST102", err.Error())
+			return
+		}
+		var updated []Row
+		for _, row := range rows {
+			if matchesAll(row, filters) {
+				for key, value := range patch {
+					row[key] = value
+				}
+				updated = append(updated, row)
+			}
+		}
+		s.respondWrite(w, http.StatusOK, updated, prefer, query.Get("select"))
+
+	case http.MethodPut:
+		var row Row
+		if err := json.NewDecoder(r.Body).Decode(&row); err != nil {
+			writeError(w, http.StatusBadRequest, "PGRST102", err.Error())
+			return
+		}
+		for _, f := range filters {
+			if f.op != "eq" || f.negate {
+				writeError(w, http.StatusMethodNotAllowed, "PGRST105", "Filters must include all and only primary key columns with 'eq' operators")
+				return
+			}
+			if !f.matches(row) {
+				writeError(w, http.StatusBadRequest, "PGRST115", "Payload values do not match URL in primary key column(s)")
+				return
+			}
+		}
+		replaced := false
+		for i, existing := range rows {
+			if matchesAll(existing, filters) {
+				rows[i] = row
+				replaced = true
+			}
+		}
+		if !replaced {
+			s.tables[table] = append(rows, row)
+		}
+		s.respondWrite(w, http.StatusOK, []Row{row}, prefer, query.Get("select"))
+
+	case http.MethodDelete:
+		var kept, deleted []Row
+		for _, row := range rows {
+			if matchesAll(row, filters) {
+				deleted = append(deleted, row)
+			} else {
+				kept = append(kept, row)
+			}
+		}
+		s.tables[table] = kept
+		s.respondWrite(w, http.StatusOK, deleted, prefer, query.Get("select"))
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "PGRST117", "unsupported HTTP method "+r.Method)
+	}
+}
+
+// limitColumns keeps only the listed columns of each row, as the columns
+// parameter does. Listed columns a row leaves out are set to null unless
+// missingDefault is set, in which case they are left out.
+func limitColumns(rows []Row, columns string, missingDefault bool) []Row {
+	if columns == "" {
+		return rows
+	}
+	limited := make([]Row, len(rows))
+	for i, row := range rows {
+		limited[i] = Row{}
+		for _, column := range strings.Split(columns, ",") {
+			if value, ok := row[column]; ok {
+				limited[i][column] = value
+			} else if !missingDefault {
+				limited[i][column] = nil
+			}
+		}
+	}
+	return limited
+}
+
+// insertRows adds inserted to rows and returns the new table and the rows
+// written. With a resolution preference, an inserted row whose on_conflict
+// columns (id by default) equal those of an existing row is merged into it
+// (merge-duplicates) or skipped (ignore-duplicates).
+func insertRows(rows, inserted []Row, resolution, onConflict string) ([]Row, []Row) {
+	if resolution != "merge-duplicates" && resolution != "ignore-duplicates" {
+		return append(rows, inserted...), inserted
+	}
+	keys := []string{"id"}
+	if onConflict != "" {
+		keys = strings.Split(onConflict, ",")
+	}
+	var written []Row
+	for _, row := range inserted {
+		existing := -1
+		for i, candidate := range rows {
+			if sameKey(candidate, row, keys) {
+				existing = i
+				break
+			}
+		}
+		switch {
+		case existing < 0:
+			rows = append(rows, row)
+			written = append(written, row)
+		case resolution == "merge-duplicates":
+			for key, value := range row {
+				rows[existing][key] = value
+			}
+			written = append(written, rows[existing])
+		}
+	}
+	return rows, written
+}
+
+// sameKey reports whether a and b have equal, non-null values in keys.
+func sameKey(a, b Row, keys []string) bool {
+	for _, key := range keys {
+		if a[key] == nil || b[key] == nil || fmt.Sprint(a[key]) != fmt.Sprint(b[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// respondWrite answers a write, honouring the return and count preferences.
+func (s *Server) respondWrite(w http.ResponseWriter, status int, rows []Row, prefer map[string]string, selectParam string) {
+	if prefer["count"] == "exact" {
+		w.Header().Set("Content-Range", contentRange(0, len(rows), len(rows), true))
+	}
+	if prefer["return"] == "representation" {
+		writeRows(w, status, rows, selectParam)
+		return
+	}
+	if status == http.StatusCreated {
+		// PostgREST answers inserts without representation with 201.
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeRows decodes a single object or an array of objects.
+func decodeRows(r *http.Request, rows *[]Row) error {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		return err
+	}
+	if trimmed := strings.TrimSpace(string(raw)); strings.HasPrefix(trimmed, "[") {
+		return json.Unmarshal(raw, rows)
+	}
+	var row Row
+	if err := json.Unmarshal(raw, &row); err != nil {
+		return err
+	}
+	*rows = []Row{row}
+	return nil
+}
+
+// parsePrefer parses Prefer headers into a map such as {"return": "representation"}.
+func parsePrefer(values []string) map[string]string {
+	prefer := map[string]string{}
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			key, val, _ := strings.Cut(strings.TrimSpace(part), "=")
+			prefer[key] = val
+		}
+	}
+	return prefer
+}
+
+// paginate applies offset and limit, returning the page and its offset.
+func paginate(rows []Row, offsetParam, limitParam string) ([]Row, int, error) {
+	offset := 0
+	if offsetParam != "" {
+		n, err := strconv.Atoi(offsetParam)
+		if err != nil || n < 0 {
+			return nil, 0, fmt.Errorf("invalid offset %q", offsetParam)
+		}
+		offset = min(n, len(rows))
+	}
+	rows = rows[offset:]
+	if limitParam != "" {
+		n, err := strconv.Atoi(limitParam)
+		if err != nil || n < 0 {
+			return nil, 0, fmt.Errorf("invalid limit %q", limitParam)
+		}
+		rows = rows[:min(n, len(rows))]
+	}
+	return rows, offset, nil
+}
+
+// contentRange formats a Content-Range header value like PostgREST.
+func contentRange(offset, n, total int, exact bool) string {
+	rangePart := "*"
+	if n > 0 {
+		rangePart = fmt.Sprintf("%d-%d", offset, offset+n-1)
+	}
+	totalPart := "*"
+	if exact {
+		totalPart = strconv.Itoa(total)
+	}
+	return rangePart + "/" + totalPart
+}
+
+// sortRows sorts rows in place by an order parameter such as "rating.desc,id".
+func sortRows(rows []Row, order string) error {
+	if order == "" {
+		return nil
+	}
+	type term struct {
+		column     string
+		desc       bool
+		nullsFirst bool
+	}
+	var terms []term
+	for _, part := range strings.Split(order, ",") {
+		fields := strings.Split(part, ".")
+		t := term{column: fields[0]}
+		nulls := ""
+		for _, modifier := range fields[1:] {
+			switch modifier {
+			case "asc":
+			case "desc":
+				t.desc = true
+			case "nullsfirst", "nullslast":
+				nulls = modifier
+			default:
+				return fmt.Errorf("invalid order modifier %q", modifier)
+			}
+		}
+		// Like Postgres, nulls sort as larger than any value by default:
+		// last ascending, first descending.
+		t.nullsFirst = nulls == "nullsfirst" || (nulls == "" && t.desc)
+		terms = append(terms, t)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, t := range terms {
+			a, b := rows[i][t.column], rows[j][t.column]
+			if a == nil || b == nil {
+				if (a == nil) == (b == nil) {
+					continue
+				}
+				return (a == nil) == t.nullsFirst
+			}
+			c := compareValues(a, toString(b))
+			if c == 0 {
+				continue
+			}
+			if t.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	return nil
+}
+
+// writeRows writes rows as a JSON array, projected to the selected columns.
+func writeRows(w http.ResponseWriter, status int, rows []Row, selectParam string) {
+	out := make([]Row, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, project(row, selectParam))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(out)
+}
+
+// project returns the selected columns of row. Aliases ("alias:column") are
+// supported; embedded resources are not.
+func project(row Row, selectParam string) Row {
+	if selectParam == "" || selectParam == "*" {
+		return copyRow(row)
+	}
+	out := Row{}
+	for _, item := range strings.Split(selectParam, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" {
+			for key, value := range row {
+				out[key] = value
+			}
+			continue
+		}
+		alias, column, ok := strings.Cut(item, ":")
+		if !ok {
+			column = alias
+		}
+		out[alias] = row[column]
+	}
+	return out
+}
+
+// writeError writes a PostgREST-style error body.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"code":    code,
+		"message": message,
+		"details": nil,
+		"hint":    nil,
+	})
+}
+
+func copyRow(row Row) Row {
+	cp := make(Row, len(row))
+	for key, value := range row {
+		cp[key] = value
+	}
+	return cp
+}
@@ -0,0 +1,179 @@
+package supabasetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/jtclarkjr/supabase-go-rest"
+)
+
+func seedFood(s *Server) {
+	s.Seed("Food",
+		Row{"id": 1, "food_name": "Ramen", "rating": 5, "opinion": nil},
+		Row{"id": 2, "food_name": "Udon", "rating": 3, "opinion": "ok"},
+		Row{"id": 3, "food_name": "Soba", "rating": 4, "opinion": "good"},
+		Row{"id": 10, "food_name": "Curry", "rating": 10, "opinion": "great"},
+	)
+}
+
+func decode(t *testing.T, body []byte) []Row {
+	t.Helper()
+	var rows []Row
+	if err := json.Unmarshal(body, &rows); err != nil {
+		t.Fatalf("Invalid JSON response %s: %v", body, err)
+	}
+	return rows
+}
+
+func TestServerGet(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	seedFood(server)
+	client := server.Client("Bearer token")
+
+	body, err := client.Get("Food", map[string]string{"food_name": "Udon"})
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if rows := decode(t, body); len(rows) != 1 || rows[0]["id"] != 2.0 {
+		t.Errorf("Unexpected rows %v", rows)
+	}
+
+	tests := []struct {
+		query url.Values
+		ids   []float64
+	}{
+		{url.Values{"rating": {"gte.4"}, "order": {"rating.asc"}}, []float64{3, 1, 10}},
+		{url.Values{"food_name": {"ilike.*O*"}, "order": {"id.desc"}}, []float64{3, 2}},
+		{url.Values{"order": {"opinion.desc"}}, []float64{1, 2, 10, 3}},
+		{url.Values{"order": {"opinion.desc.nullslast"}}, []float64{2, 10, 3, 1}},
+		{url.Values{"order": {"opinion.asc"}}, []float64{3, 10, 2, 1}},
+		{url.Values{"order": {"opinion.asc.nullsfirst"}}, []float64{1, 3, 10, 2}},
+		{url.Values{"id": {"in.(1,3)"}}, []float64{1, 3}},
+		{url.Values{"opinion": {"is.null"}}, []float64{1}},
+		{url.Values{"opinion": {"not.is.null"}, "order": {"id"}, "limit": {"1"}, "offset": {"1"}}, []float64{3}},
+		{url.Values{"rating": {"lt.10"}, "id": {"gt.1"}}, []float64{2, 3}},
+		{url.Values{"rating": {"not.eq.5"}, "food_name": {"neq.Soba"}}, []float64{2, 10}},
+	}
+	for _, tt := range tests {
+		resp, err := client.Execute(http.MethodGet, "Food", tt.query, nil)
+		if err != nil {
+			t.Errorf("Execute(%v) returned error: %v", tt.query, err)
+			continue
+		}
+		rows := decode(t, resp.Body)
+		var ids []float64
+		for _, row := range rows {
+			ids = append(ids, row["id"].(float64))
+		}
+		if len(ids) != len(tt.ids) {
+			t.Errorf("Execute(%v) returned ids %v, want %v", tt.query, ids, tt.ids)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != tt.ids[i] {
+				t.Errorf("Execute(%v) returned ids %v, want %v", tt.query, ids, tt.ids)
+				break
+			}
+		}
+	}
+}
+
+func TestServerSelectAndCount(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	seedFood(server)
+	client := server.Client("Bearer token")
+
+	resp, err := client.Execute(http.MethodGet, "Food?select=name:food_name&limit=2&order=id", nil, nil)
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	rows := decode(t, resp.Body)
+	if len(rows) != 2 || rows[0]["name"] != "Ramen" || len(rows[0]) != 1 {
+		t.Errorf("Unexpected projected rows %v", rows)
+	}
+	if got := resp.Header.Get("Content-Range"); got != "0-1/*" {
+		t.Errorf("Expected Content-Range 0-1/*, got %q", got)
+	}
+}
+
+func TestServerWrites(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	seedFood(server)
+	client := server.Client("Bearer token")
+
+	if _, err := client.Post("Food", []byte(`[{"id":4,"food_name":"Pho","rating":5}]`)); err != nil {
+		t.Fatalf("Post returned error: %v", err)
+	}
+	if _, err := client.Patch("Food", map[string]string{"id": "2"}, []byte(`{"rating":1}`)); err != nil {
+		t.Fatalf("Patch returned error: %v", err)
+	}
+	if _, err := client.Delete("Food", "id", "3"); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+	if _, err := client.Put("Food", "id", "1", []byte(`{"id":1,"food_name":"Tonkotsu","rating":5}`)); err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	var apiErr *supabase.APIError
+	if _, err := client.Execute(http.MethodPut, "Food", url.Values{"id": {"eq.1"}}, []byte(`{"id":2}`)); !errors.As(err, &apiErr) || apiErr.Code != "PGRST115" {
+		t.Fatalf("Expected PGRST115 for mismatched PUT, got %v", err)
+	}
+
+	rows := server.Rows("Food")
+	if len(rows) != 4 {
+		t.Fatalf("Expected 4 rows, got %v", rows)
+	}
+	if rows[0]["food_name"] != "Tonkotsu" || rows[1]["rating"] != 1.0 || rows[3]["food_name"] != "Pho" {
+		t.Errorf("Unexpected rows after writes %v", rows)
+	}
+}
+
+func TestServerUpsert(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	seedFood(server)
+	client := server.Client("Bearer token")
+	ctx := context.Background()
+
+	body, err := client.Upsert(ctx, "Food", []byte(`[{"id":1,"rating":2},{"id":5,"food_name":"Pho","rating":5}]`), supabase.UpsertOptions{Return: true})
+	if err != nil {
+		t.Fatalf("Upsert returned error: %v", err)
+	}
+	if written := decode(t, body); len(written) != 2 || written[0]["food_name"] != "Ramen" || written[0]["rating"] != 2.0 {
+		t.Errorf("Expected the merged and inserted rows, got %v", written)
+	}
+	if _, err := client.Upsert(ctx, "Food", []byte(`{"food_name":"Udon","rating":9}`), supabase.UpsertOptions{OnConflict: []supabase.Column{"food_name"}, IgnoreDuplicates: true}); err != nil {
+		t.Fatalf("Upsert returned error: %v", err)
+	}
+	if _, err := client.Insert(ctx, "Food", []byte(`{"id":6,"extra":true}`), "id", "food_name"); err != nil {
+		t.Fatalf("Insert returned error: %v", err)
+	}
+
+	rows := server.Rows("Food")
+	if len(rows) != 6 {
+		t.Fatalf("Expected upserts to merge into existing rows, got %v", rows)
+	}
+	if rows[0]["rating"] != 2.0 || rows[0]["food_name"] != "Ramen" || rows[1]["rating"] != 3.0 {
+		t.Errorf("Unexpected rows after upserts %v", rows)
+	}
+	if _, ok := rows[5]["extra"]; ok || rows[5]["id"] != 6.0 {
+		t.Errorf("Expected Insert to write only the listed columns, got %v", rows[5])
+	}
+}
+
+func TestServerMissingTable(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	_, err := server.Client("Bearer token").Get("Missing")
+	var apiErr *supabase.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 APIError, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWithTenant(t *testing.T) {
+	type request struct {
+		method, query, body string
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, request{r.Method, r.URL.RawQuery, string(body)})
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	base := NewClient(server.URL, "key", "token")
+	client := base.WithTenant("org_id", 42)
+
+	client.Get("projects", map[string]string{"id": "1"})
+	client.Post("projects", []byte(`[{"name":"a"},{"name":"b","org_id":42}]`))
+	client.Patch("projects", map[string]string{"id": "1"}, []byte(`{"name":"c"}`))
+	client.Put("projects", "id", "1", []byte(`{"id":1,"name":"d"}`))
+	client.Delete("projects", "id", "1")
+	client.Insert(context.Background(), "projects", []byte(`{"name":"e"}`), "name")
+	client.Post("rpc/archive", []byte(`{}`))
+	base.Get("projects")
+
+	want := []request{
+		{http.MethodGet, "id=eq.1&org_id=eq.42", ""},
+		{http.MethodPost, "", `[{"name":"a","org_id":42},{"name":"b","org_id":42}]`},
+		{http.MethodPatch, "id=eq.1&org_id=eq.42", `{"name":"c","org_id":42}`},
+		{http.MethodPut, "id=eq.1", `{"id":1,"name":"d","org_id":42}`},
+		{http.MethodDelete, "id=eq.1&org_id=eq.42", ""},
+		{http.MethodPost, "columns=name%2Corg_id", `{"name":"e","org_id":42}`},
+		{http.MethodPost, "", `{}`},
+		{http.MethodGet, "", ""},
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("Expected %d requests, got %+v", len(want), requests)
+	}
+	for i, w := range want {
+		got := requests[i]
+		gotQuery, _ := url.ParseQuery(got.query)
+		wantQuery, _ := url.ParseQuery(w.query)
+		if got.method != w.method || gotQuery.Encode() != wantQuery.Encode() || got.body != w.body {
+			t.Errorf("Request %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+
+	requests = nil
+	_, err := client.Post("projects", []byte(`{"name":"x","org_id":7}`))
+	if !errors.Is(err, ErrTenantMismatch) || len(requests) != 0 {
+		t.Errorf("Expected ErrTenantMismatch without a request, got %v after %d requests", err, len(requests))
+	}
+}
+
+func TestWithTenantBulkAndQueue(t *testing.T) {
+	var queries, bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		queries, bodies = append(queries, r.URL.RawQuery), append(bodies, string(body))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token").WithTenant("org_id", 42)
+	ctx := context.Background()
+
+	writer := NewBulkWriter[map[string]any](client, "projects")
+	if err := writer.Write(ctx, []map[string]any{{"name": "a"}}); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if len(bodies) != 1 || bodies[0] != `[{"name":"a","org_id":42}]` {
+		t.Errorf("Expected BulkWriter rows to be scoped, got %v", bodies)
+	}
+	bodies = nil
+	err := writer.Write(ctx, []map[string]any{{"name": "b", "org_id": 7}})
+	if !errors.Is(err, ErrTenantMismatch) || len(bodies) != 0 {
+		t.Errorf("Expected ErrTenantMismatch without a request, got %v after %d requests", err, len(bodies))
+	}
+
+	// Writes queued by another client are scoped by the replaying one.
+	queue, err := OpenWriteQueue(filepath.Join(t.TempDir(), "writes.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenWriteQueue returned error: %v", err)
+	}
+	queue.Enqueue(QueuedWrite{IdempotencyKey: "k1", Method: http.MethodPost, Endpoint: "projects", Body: []byte(`{"name":"c","org_id":7}`)})
+	queue.Enqueue(QueuedWrite{IdempotencyKey: "k2", Method: http.MethodDelete, Endpoint: "projects", Query: url.Values{"id": {"eq.1"}}})
+	queries = nil
+	var dropped []string
+	queue.OnDrop = func(w QueuedWrite, err error) { dropped = append(dropped, w.IdempotencyKey) }
+	n, err := queue.Replay(ctx, client)
+	if n != 1 || !errors.Is(err, ErrTenantMismatch) || !slices.Equal(dropped, []string{"k1"}) {
+		t.Errorf("Expected the cross-tenant write to be dropped, got %d %v (%v)", n, dropped, err)
+	}
+	if len(queries) != 1 || queries[0] != "id=eq.1&org_id=eq.42" {
+		t.Errorf("Expected the replayed delete to be scoped, got %v", queries)
+	}
+	if pending, err := queue.Pending(); err != nil || len(pending) != 0 {
+		t.Errorf("Expected an empty queue after replay, got %v (%v)", pending, err)
+	}
+}
+
+func TestWithTenantBatch(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token").WithTenant("org_id", 42)
+
+	_, err := client.Batch("run_batch").Insert("projects", map[string]any{"name": "a"}).Execute(context.Background())
+	if err == nil || requests != 0 {
+		t.Errorf("Expected Batch to be refused without a request, got %v after %d requests", err, requests)
+	}
+}
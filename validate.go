@@ -0,0 +1,234 @@
+package supabase
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// QueryError is returned when a query parameter is malformed, before the
+// request is sent. Validation can be turned off with WithoutQueryValidation.
+type QueryError struct {
+	Param  string
+	Value  string
+	Reason string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("supabase: invalid query parameter %s=%s: %s", e.Param, e.Value, e.Reason)
+}
+
+// WithoutQueryValidation disables the client-side checks of filter
+// operators, order, and select syntax, e.g. for operators added in newer
+// PostgREST versions.
+func WithoutQueryValidation() Option {
+	return func(c *Client) {
+		c.skipQueryValidation = true
+	}
+}
+
+// filterOperators are the PostgREST operators accepted in filters.
+var filterOperators = map[string]bool{
+	"eq": true, "neq": true, "gt": true, "gte": true, "lt": true, "lte": true,
+	"like": true, "ilike": true, "match": true, "imatch": true,
+	"in": true, "is": true, "isdistinct": true,
+	"fts": true, "plfts": true, "phfts": true, "wfts": true,
+	"cs": true, "cd": true, "ov": true,
+	"sl": true, "sr": true, "nxr": true, "nxl": true, "adj": true,
+}
+
+// validate checks a request on the client side before it is sent, so
+// mistakes surface as descriptive errors rather than server responses.
+func (c *Client) validate(endpoint string, query url.Values, body []byte) error {
+	if !c.skipQueryValidation {
+		if err := validateQuery(endpoint, query); err != nil {
+			return err
+		}
+	}
+	return c.validateEnums(endpoint, query, body)
+}
+
+// validateQuery checks the query string of endpoint and query. Arguments of
+// functions called through rpc/ are passed as plain parameters and are not
+// checked.
+func validateQuery(endpoint string, query url.Values) error {
+	path, rawQuery, _ := strings.Cut(endpoint, "?")
+	if strings.HasPrefix(path, "rpc/") {
+		return nil
+	}
+	if rawQuery != "" {
+		extra, err := url.ParseQuery(rawQuery)
+		if err != nil {
+			return &QueryError{Param: "?", Value: rawQuery, Reason: err.Error()}
+		}
+		if err := validateValues(extra); err != nil {
+			return err
+		}
+	}
+	return validateValues(query)
+}
+
+func validateValues(query url.Values) error {
+	for param, values := range query {
+		for _, value := range values {
+			if err := validateParam(param, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateParam checks one parameter. Parameters on embedded resources are
+// prefixed with the resource name, e.g. "items.order".
+func validateParam(param, value string) error {
+	name := param
+	if i := strings.LastIndex(param, "."); i >= 0 && !strings.HasPrefix(param, "not.") {
+		name = param[i+1:]
+	}
+	var reason string
+	switch name {
+	case "select":
+		reason = validateSelect(value)
+	case "order":
+		reason = validateOrder(value)
+	case "limit", "offset":
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			reason = "must be a non-negative integer"
+		}
+	case "or", "and", "not.or", "not.and":
+		reason = validateLogic(value)
+	case "on_conflict", "columns":
+		if value == "" {
+			reason = "must not be empty"
+		}
+	default:
+		reason = validateFilter(value)
+	}
+	if reason != "" {
+		return &QueryError{Param: param, Value: value, Reason: reason}
+	}
+	return nil
+}
+
+// validateFilter checks a filter value such as "gte.4" or "not.in.(1,2)".
+func validateFilter(value string) string {
+	value = strings.TrimPrefix(value, "not.")
+	op, operand, ok := strings.Cut(value, ".")
+	if !ok {
+		return `expected "operator.value", e.g. "eq.` + value + `"`
+	}
+	// Operators may carry a modifier, e.g. fts(english) or eq(any).
+	if i := strings.IndexByte(op, '('); i >= 0 && strings.HasSuffix(op, ")") {
+		op = op[:i]
+	}
+	if !filterOperators[op] {
+		return fmt.Sprintf("unknown operator %q", op)
+	}
+	switch op {
+	case "in":
+		if !strings.HasPrefix(operand, "(") || !strings.HasSuffix(operand, ")") {
+			return "in requires a parenthesized list, e.g. in.(1,2,3)"
+		}
+	case "is":
+		switch strings.ToLower(operand) {
+		case "null", "not_null", "true", "false", "unknown":
+		default:
+			return "is accepts null, not_null, true, false, or unknown"
+		}
+	}
+	return ""
+}
+
+// validateOrder checks an order value such as "rating.desc.nullslast,id".
+func validateOrder(value string) string {
+	items, reason := splitTopLevel(value)
+	if reason != "" {
+		return reason
+	}
+	for _, item := range items {
+		// Modifiers follow the last closing parenthesis of an embedded
+		// column or quote of a JSON key, e.g. author(name).asc or
+		// data->>"a.b".desc.
+		column, modifiers := item, ""
+		start := max(strings.LastIndexByte(item, ')'), strings.LastIndexByte(item, '"')) + 1
+		if i := strings.IndexByte(item[start:], '.'); i >= 0 {
+			column, modifiers = item[:start+i], item[start+i+1:]
+		}
+		if column == "" {
+			return "missing column name"
+		}
+		var direction, nulls bool
+		for _, m := range strings.Split(modifiers, ".") {
+			switch {
+			case m == "" && modifiers == "":
+			case (m == "asc" || m == "desc") && !direction:
+				direction = true
+			case (m == "nullsfirst" || m == "nullslast") && !nulls:
+				nulls = true
+			default:
+				return fmt.Sprintf("unknown order modifier %q in %q", m, item)
+			}
+		}
+	}
+	return ""
+}
+
+// validateSelect checks a select value such as "id,author:users(name)".
+func validateSelect(value string) string {
+	_, reason := splitTopLevel(value)
+	return reason
+}
+
+// validateLogic checks an or/and value such as "(rating.gte.4,id.eq.1)".
+func validateLogic(value string) string {
+	if !strings.HasPrefix(value, "(") || !strings.HasSuffix(value, ")") {
+		return "expected a parenthesized list of conditions"
+	}
+	_, reason := splitTopLevel(value[1 : len(value)-1])
+	return reason
+}
+
+// splitTopLevel splits a comma-separated list, ignoring commas inside
+// parentheses and double quotes, and reports unbalanced or empty items.
+func splitTopLevel(value string) ([]string, string) {
+	var (
+		items  []string
+		depth  int
+		quoted bool
+		start  int
+	)
+	for i := 0; i < len(value); i++ {
+		switch ch := value[i]; {
+		case ch == '\\' && quoted:
+			i++
+		case ch == '"':
+			quoted = !quoted
+		case quoted:
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+			if depth < 0 {
+				return nil, "unbalanced parentheses"
+			}
+		case ch == ',' && depth == 0:
+			items = append(items, value[start:i])
+			start = i + 1
+		}
+	}
+	switch {
+	case quoted:
+		return nil, "unterminated quote"
+	case depth != 0:
+		return nil, "unbalanced parentheses"
+	}
+	items = append(items, value[start:])
+	for _, item := range items {
+		if strings.TrimSpace(item) == "" {
+			return nil, "empty item in list"
+		}
+	}
+	return items, ""
+}
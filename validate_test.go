@@ -0,0 +1,70 @@
+package supabase
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestValidateQuery(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		query    url.Values
+		wantErr  string
+	}{
+		{"Food", url.Values{"rating": {"gte.4"}, "select": {"id,author:users(name,posts(id))"}, "order": {"rating.desc.nullslast,id"}, "limit": {"10"}}, ""},
+		{"Food", url.Values{"id": {"not.in.(1,2)"}, "or": {"(rating.gte.4,and(id.eq.1,id.eq.2))"}, "tsv": {"fts(english).cat"}}, ""},
+		{"Food", url.Values{"items.order": {"author(name).asc"}, "items.limit": {"1"}, "deleted_at": {"is.NULL"}}, ""},
+		{"rpc/search?term=cat", url.Values{"limit": {"5"}}, ""},
+		{"rpc/search", url.Values{"term": {"cat"}}, ""},
+		{"Food", url.Values{"rating": {"4"}}, `expected "operator.value"`},
+		{"Food", url.Values{"rating": {"gteq.4"}}, `unknown operator "gteq"`},
+		{"Food", url.Values{"id": {"in.1,2"}}, "parenthesized list"},
+		{"Food", url.Values{"deleted_at": {"is.nothing"}}, "is accepts"},
+		{"Food", url.Values{"order": {"rating.down"}}, `unknown order modifier "down"`},
+		{"Food", url.Values{"order": {"rating.asc.desc"}}, `unknown order modifier "desc"`},
+		{"Food", url.Values{"order": {".asc"}}, "missing column name"},
+		{"Food", url.Values{"select": {"id,author(name"}}, "unbalanced parentheses"},
+		{"Food", url.Values{"select": {"id,,name"}}, "empty item"},
+		{"Food", url.Values{"limit": {"-1"}}, "non-negative integer"},
+		{"Food", url.Values{"or": {"rating.gte.4"}}, "parenthesized list of conditions"},
+		{"Food?rating=4", nil, `expected "operator.value"`},
+	}
+	for _, tt := range tests {
+		err := validateQuery(tt.endpoint, tt.query)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("validateQuery(%q, %v) returned error: %v", tt.endpoint, tt.query, err)
+			}
+			continue
+		}
+		var queryErr *QueryError
+		if !errors.As(err, &queryErr) || !strings.Contains(queryErr.Reason, tt.wantErr) {
+			t.Errorf("validateQuery(%q, %v) = %v, want reason containing %q", tt.endpoint, tt.query, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWithoutQueryValidation(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	query := url.Values{"rating": {"newop.4"}}
+	_, err := NewClient(server.URL, "key", "token").Execute("GET", "Food", query, nil)
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) || requests != 0 {
+		t.Errorf("Expected *QueryError without a request, got %v after %d requests", err, requests)
+	}
+
+	client := NewClient(server.URL, "key", "token", WithoutQueryValidation())
+	if _, err := client.Execute("GET", "Food", query, nil); err != nil || requests != 1 {
+		t.Errorf("Expected request to be sent, got %v after %d requests", err, requests)
+	}
+}
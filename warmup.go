@@ -0,0 +1,38 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Warmup resolves the Supabase host and opens a connection to the REST API so
+// the first user-facing request after startup can reuse an established TLS
+// connection instead of paying for DNS and the handshake. The request goes
+// through the client's usual pipeline, so signers and request hooks apply.
+// The response status is ignored; only failures to resolve or connect are
+// returned.
+func (c *Client) Warmup(ctx context.Context) error {
+	u, err := url.Parse(c.ServiceURL(ServiceREST))
+	if err != nil {
+		return fmt.Errorf("failed to parse URL: %v", err)
+	}
+	// A custom dialer may pin the host to addresses DNS does not know about.
+	if c.dialContext == nil {
+		if _, err := c.lookupResolver().LookupHost(ctx, u.Hostname()); err != nil {
+			return fmt.Errorf("failed to resolve host: %w", err)
+		}
+	}
+
+	cp := c.clone()
+	cp.rootPath = true
+	_, err = cp.execute(ctx, http.MethodHead, c.serviceEndpoint(ServiceREST, ""), nil, nil, nil)
+	var apiErr *APIError
+	var dryRun *DryRunError
+	if errors.As(err, &apiErr) || errors.As(err, &dryRun) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package supabase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWarmup(t *testing.T) {
+	var hits int
+	var apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		apiKey = r.Header.Get("apikey")
+		if r.Method != http.MethodHead || r.URL.Path != restApiPath+"/" {
+			t.Errorf("unexpected warmup request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "your_api_key", "")
+	if err := client.Warmup(context.Background()); err != nil {
+		t.Fatalf("Warmup returned error: %v", err)
+	}
+	if hits != 1 {
+		t.Errorf("Expected 1 warmup request, got %d", hits)
+	}
+	if apiKey != "your_api_key" {
+		t.Errorf("Expected apikey header to be set, got %q", apiKey)
+	}
+}
+
+func TestWarmupUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	baseUrl := server.URL
+	server.Close()
+
+	client := NewClient(baseUrl, "your_api_key", "")
+	if err := client.Warmup(context.Background()); err == nil {
+		t.Error("Expected error warming up a closed server")
+	}
+}
@@ -0,0 +1,83 @@
+package supabase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrWebhookUnauthorized is returned when a webhook request does not carry
+// the expected secret or signature.
+var ErrWebhookUnauthorized = errors.New("supabase: webhook secret or signature mismatch")
+
+// WebhookEventType is the operation that triggered a database webhook.
+type WebhookEventType string
+
+const (
+	WebhookInsert WebhookEventType = "INSERT"
+	WebhookUpdate WebhookEventType = "UPDATE"
+	WebhookDelete WebhookEventType = "DELETE"
+)
+
+// WebhookPayload is the body Supabase Database Webhooks send, with the row
+// decoded into T. Record is nil for deletes and OldRecord is nil for inserts.
+type WebhookPayload[T any] struct {
+	Type      WebhookEventType `json:"type"`
+	Table     string           `json:"table"`
+	Schema    string           `json:"schema"`
+	Record    *T               `json:"record"`
+	OldRecord *T               `json:"old_record"`
+}
+
+// DecodeWebhook decodes a database webhook body:
+//
+//	payload, err := supabase.DecodeWebhook[Order](body)
+//	if payload.Type == supabase.WebhookUpdate && payload.OldRecord.Status != payload.Record.Status {
+//		// status changed
+//	}
+func DecodeWebhook[T any](body []byte) (*WebhookPayload[T], error) {
+	var payload WebhookPayload[T]
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook: %v", err)
+	}
+	switch payload.Type {
+	case WebhookInsert, WebhookUpdate, WebhookDelete:
+	default:
+		return nil, fmt.Errorf("failed to decode webhook: unknown type %q", payload.Type)
+	}
+	return &payload, nil
+}
+
+// VerifyWebhookSecret checks that header of r holds secret, the shared
+// secret configured as an HTTP header on the webhook in the dashboard. A
+// "Bearer " prefix is accepted, so the secret can also be sent as the
+// Authorization header. The comparison takes constant time.
+func VerifyWebhookSecret(r *http.Request, header, secret string) error {
+	got := strings.TrimPrefix(r.Header.Get(header), "Bearer ")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(got), []byte(secret)) != 1 {
+		return ErrWebhookUnauthorized
+	}
+	return nil
+}
+
+// VerifyWebhookSignature checks that signature is the hex HMAC-SHA256 of body
+// under secret, optionally prefixed with "sha256=", for webhooks routed
+// through a signing proxy or an edge function.
+func VerifyWebhookSignature(body []byte, signature, secret string) error {
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || secret == "" {
+		return ErrWebhookUnauthorized
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrWebhookUnauthorized
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package supabase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+type webhookOrder struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+func TestDecodeWebhook(t *testing.T) {
+	body := []byte(`{"type":"UPDATE","table":"orders","schema":"public","record":{"id":1,"status":"paid"},"old_record":{"id":1,"status":"open"}}`)
+	payload, err := DecodeWebhook[webhookOrder](body)
+	if err != nil {
+		t.Fatalf("DecodeWebhook returned error: %v", err)
+	}
+	if payload.Type != WebhookUpdate || payload.Table != "orders" || payload.Record.Status != "paid" || payload.OldRecord.Status != "open" {
+		t.Errorf("Unexpected payload %+v", payload)
+	}
+
+	payload, err = DecodeWebhook[webhookOrder]([]byte(`{"type":"INSERT","table":"orders","schema":"public","record":{"id":2},"old_record":null}`))
+	if err != nil || payload.OldRecord != nil || payload.Record.ID != 2 {
+		t.Errorf("Expected insert without old record, got %+v (%v)", payload, err)
+	}
+
+	if _, err := DecodeWebhook[webhookOrder]([]byte(`{"type":"TRUNCATE"}`)); err == nil {
+		t.Error("Expected error for unknown type")
+	}
+}
+
+func TestVerifyWebhookSecret(t *testing.T) {
+	r := httptest.NewRequest("POST", "/hook", nil)
+	r.Header.Set("X-Webhook-Secret", "s3cret")
+	if err := VerifyWebhookSecret(r, "X-Webhook-Secret", "s3cret"); err != nil {
+		t.Errorf("Expected valid secret, got %v", err)
+	}
+	r.Header.Set("Authorization", "Bearer s3cret")
+	if err := VerifyWebhookSecret(r, "Authorization", "s3cret"); err != nil {
+		t.Errorf("Expected valid bearer secret, got %v", err)
+	}
+	if err := VerifyWebhookSecret(r, "X-Webhook-Secret", "other"); !errors.Is(err, ErrWebhookUnauthorized) {
+		t.Errorf("Expected ErrWebhookUnauthorized, got %v", err)
+	}
+	if err := VerifyWebhookSecret(httptest.NewRequest("POST", "/hook", nil), "X-Webhook-Secret", ""); !errors.Is(err, ErrWebhookUnauthorized) {
+		t.Errorf("Expected empty secret to be rejected, got %v", err)
+	}
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"type":"INSERT"}`)
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	for _, sig := range []string{signature, "sha256=" + signature} {
+		if err := VerifyWebhookSignature(body, sig, "key"); err != nil {
+			t.Errorf("Expected valid signature %s, got %v", sig, err)
+		}
+	}
+	for _, sig := range []string{"", "zz", signature[:10]} {
+		if err := VerifyWebhookSignature(body, sig, "key"); !errors.Is(err, ErrWebhookUnauthorized) {
+			t.Errorf("Expected ErrWebhookUnauthorized for %q, got %v", sig, err)
+		}
+	}
+	if err := VerifyWebhookSignature([]byte(`{}`), signature, "key"); !errors.Is(err, ErrWebhookUnauthorized) {
+		t.Errorf("Expected tampered body to be rejected, got %v", err)
+	}
+}
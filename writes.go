@@ -0,0 +1,223 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnguardedDelete is returned by DeleteWhere when the guard sets
+	// neither MaxRows nor AllowFullTable.
+	ErrUnguardedDelete = errors.New("supabase: DeleteWhere requires MaxRows or AllowFullTable")
+	// ErrFullTableDelete is returned by DeleteWhere when no filters are given
+	// and the guard does not allow deleting the whole table.
+	ErrFullTableDelete = errors.New("supabase: delete without filters requires AllowFullTable")
+	// ErrTooManyRows is returned when a guarded write would affect more rows
+	// than allowed. Nothing is changed.
+	ErrTooManyRows = errors.New("supabase: write exceeds maximum affected rows")
+	// ErrStaleRow is returned by UpdateIfVersion when no row has the primary
+	// key and expected version, because another writer changed the row, or
+	// it was deleted or is hidden by row-level security.
+	ErrStaleRow = errors.New("supabase: row changed since it was read")
+)
+
+// DeleteGuard bounds what DeleteWhere may remove. One of its fields must be
+// set, so a forgotten filter cannot silently empty a table.
+type DeleteGuard struct {
+	// MaxRows is the most rows the delete may remove. If more rows match,
+	// PostgREST rolls the delete back and DeleteWhere returns ErrTooManyRows.
+	// Enforcement needs PostgREST 12 or later.
+	MaxRows int
+	// AllowFullTable permits a delete without filters and, without MaxRows,
+	// of any number of rows.
+	AllowFullTable bool
+}
+
+// DeleteWhere deletes the rows of table matching all filters, within the
+// limits of guard, and returns the deleted rows as a JSON array and their
+// number:
+//
+//	rows, n, err := client.DeleteWhere(ctx, "sessions", supabase.DeleteGuard{MaxRows: 100},
+//		supabase.Eq("user_id", userID))
+func (c *Client) DeleteWhere(ctx context.Context, table string, guard DeleteGuard, filters ...Filter) ([]byte, int, error) {
+	if guard.MaxRows <= 0 && !guard.AllowFullTable {
+		return nil, 0, ErrUnguardedDelete
+	}
+	if len(filters) == 0 && !guard.AllowFullTable {
+		return nil, 0, ErrFullTableDelete
+	}
+	query := url.Values{}
+	for _, f := range filters {
+		key, value := f.param()
+		query.Add(key, value)
+	}
+	client := c.withPrefer("return=representation")
+	if guard.MaxRows > 0 {
+		client = client.withPrefer("handling=strict").withPrefer("max-affected=" + strconv.Itoa(guard.MaxRows))
+	}
+	resp, err := client.send(ctx, http.MethodDelete, table, query, nil)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Code == "PGRST124" {
+			return nil, 0, fmt.Errorf("%w: %v", ErrTooManyRows, err)
+		}
+		return nil, 0, err
+	}
+	n, err := c.affectedRows(resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Body, n, nil
+}
+
+// UpdateWhereIn applies patch to every row of table whose column is one of
+// values with a single PATCH ...?column=in.(...) request, instead of one Patch
+// call per row. It returns the updated rows as a JSON array and their number.
+// No request is sent when values is empty.
+func UpdateWhereIn[T any](ctx context.Context, c *Client, table string, column Column, values []T, patch []byte) ([]byte, int, error) {
+	if len(values) == 0 {
+		return []byte("[]"), 0, nil
+	}
+	query := url.Values{}
+	key, value := In(column, values).param()
+	query.Add(key, value)
+	resp, err := c.withPrefer("return=representation").send(ctx, http.MethodPatch, table, query, patch)
+	if err != nil {
+		return nil, 0, err
+	}
+	n, err := c.affectedRows(resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Body, n, nil
+}
+
+// UpdateIfVersion applies patch to the row of table matching pk only if it
+// still matches expected, a filter on a version column that every write
+// changes, such as an integer bumped by the patch or an updated_at column set
+// by a trigger. This gives check-and-set updates without a stored procedure:
+//
+//	row, err := client.UpdateIfVersion(ctx, "documents", supabase.Eq("id", doc.ID),
+//		supabase.Eq("version", doc.Version),
+//		[]byte(fmt.Sprintf(`{"body": %q, "version": %d}`, body, doc.Version+1)))
+//	if errors.Is(err, supabase.ErrStaleRow) {
+//		// reload and retry, or report a conflict
+//	}
+//
+// It returns the updated row as a JSON object, or ErrStaleRow when no row was
+// updated.
+func (c *Client) UpdateIfVersion(ctx context.Context, table string, pk, expected Filter, patch []byte) ([]byte, error) {
+	query := url.Values{}
+	for _, f := range []Filter{pk, expected} {
+		key, value := f.param()
+		query.Add(key, value)
+	}
+	resp, err := c.withPrefer("return=representation").send(ctx, http.MethodPatch, table, query, patch)
+	if err != nil {
+		return nil, err
+	}
+	var rows []json.RawMessage
+	if err := c.getCodec().Unmarshal(resp.Body, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	switch len(rows) {
+	case 0:
+		return nil, ErrStaleRow
+	case 1:
+		return rows[0], nil
+	}
+	return nil, fmt.Errorf("primary key filter %s matched %d rows", pk, len(rows))
+}
+
+// affectedRows returns the number of rows a write touched, from the
+// Content-Range header or, failing that, the returned representation.
+func (c *Client) affectedRows(resp *Response) (int, error) {
+	if info, ok := resp.PageInfo(); ok {
+		if info.Total >= 0 {
+			return info.Total, nil
+		}
+		return info.To - info.From + 1, nil
+	}
+	var rows []json.RawMessage
+	if err := c.getCodec().Unmarshal(resp.Body, &rows); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return len(rows), nil
+}
+
+// UpsertOptions configures Upsert.
+type UpsertOptions struct {
+	// OnConflict lists the columns of the unique constraint that decides
+	// whether a row already exists. It defaults to the primary key.
+	OnConflict []Column
+	// IgnoreDuplicates skips rows that conflict with existing ones instead of
+	// merging them into the existing rows.
+	IgnoreDuplicates bool
+	// Columns limits the columns written, as for Insert. Rows missing a
+	// listed column are written with null there, which a merge copies onto
+	// the existing row, so every row should carry every listed column.
+	Columns []Column
+	// Return asks for the written rows. With IgnoreDuplicates only the rows
+	// actually inserted are returned, so skipped rows can be told apart.
+	Return bool
+}
+
+// Upsert inserts rows (a JSON object or array) into table, resolving
+// conflicts with existing rows as set in opts. It returns the written rows
+// when opts.Return is set and an empty body otherwise.
+func (c *Client) Upsert(ctx context.Context, table string, rows []byte, opts UpsertOptions) ([]byte, error) {
+	query := url.Values{}
+	if len(opts.OnConflict) > 0 {
+		query.Set("on_conflict", joinColumns(opts.OnConflict))
+	}
+	if len(opts.Columns) > 0 {
+		query.Set("columns", joinColumns(opts.Columns))
+	}
+	client := c.withPrefer("resolution=merge-duplicates")
+	if opts.IgnoreDuplicates {
+		client = c.withPrefer("resolution=ignore-duplicates")
+	}
+	if opts.Return {
+		client = client.withPrefer("return=representation")
+	}
+	resp, err := client.send(ctx, http.MethodPost, table, query, rows)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Insert inserts rows (a JSON object or array) into table. When columns are
+// given, only those columns are written and any other keys in the payload
+// are ignored by PostgREST, which makes it safe to insert third-party
+// payloads as received. Rows missing a listed column get its default, as the
+// request asks for Prefer: missing=default. Use WithRepresentation to get the
+// inserted rows back.
+func (c *Client) Insert(ctx context.Context, table string, rows []byte, columns ...Column) ([]byte, error) {
+	query := url.Values{}
+	client := c
+	if len(columns) > 0 {
+		query.Set("columns", joinColumns(columns))
+		client = c.withPrefer("missing=default")
+	}
+	resp, err := client.send(ctx, http.MethodPost, table, query, rows)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// joinColumns renders columns as a comma-separated list.
+func joinColumns(columns []Column) string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = string(column)
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,184 @@
+package supabase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestUpdateWhereIn(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPatch || r.URL.Query().Get("id") != "in.(1,2,3)" || string(body) != `{"done":true}` {
+			t.Errorf("Unexpected request %s %s %s", r.Method, r.URL.RawQuery, body)
+		}
+		if got := r.Header.Get("Prefer"); got != "return=representation" {
+			t.Errorf("Expected Prefer return=representation, got %q", got)
+		}
+		w.Header().Set("Content-Range", "0-1/*")
+		w.Write([]byte(`[{"id":1,"done":true},{"id":3,"done":true}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "token")
+	rows, n, err := UpdateWhereIn(context.Background(), client, "todos", "id", []int{1, 2, 3}, []byte(`{"done":true}`))
+	if err != nil {
+		t.Fatalf("UpdateWhereIn returned error: %v", err)
+	}
+	if n != 2 || len(rows) == 0 {
+		t.Errorf("Expected 2 affected rows, got %d (%s)", n, rows)
+	}
+
+	if _, n, err := UpdateWhereIn(context.Background(), client, "todos", "id", []int{}, []byte(`{}`)); err != nil || n != 0 {
+		t.Errorf("Expected no-op for empty values, got %d, %v", n, err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func TestDeleteWhere(t *testing.T) {
+	var gotQuery, gotPrefer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery, gotPrefer = r.URL.RawQuery, r.Header.Get("Prefer")
+		if r.URL.Query().Get("user_id") == "eq.all" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":"PGRST124","message":"Query result exceeds max-affected preference constraint"}`))
+			return
+		}
+		w.Write([]byte(`[{"id":1}]`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token")
+	ctx := context.Background()
+
+	if _, _, err := client.DeleteWhere(ctx, "sessions", DeleteGuard{}, Eq("user_id", 7)); !errors.Is(err, ErrUnguardedDelete) {
+		t.Errorf("Expected ErrUnguardedDelete, got %v", err)
+	}
+	if _, _, err := client.DeleteWhere(ctx, "sessions", DeleteGuard{MaxRows: 10}); !errors.Is(err, ErrFullTableDelete) {
+		t.Errorf("Expected ErrFullTableDelete, got %v", err)
+	}
+	if gotQuery != "" {
+		t.Fatalf("Expected no request for rejected deletes, got %s", gotQuery)
+	}
+
+	_, n, err := client.DeleteWhere(ctx, "sessions", DeleteGuard{MaxRows: 10}, Eq("user_id", 7))
+	if err != nil || n != 1 {
+		t.Fatalf("Expected 1 deleted row, got %d, %v", n, err)
+	}
+	if gotQuery != "user_id=eq.7" || gotPrefer != "return=representation, handling=strict, max-affected=10" {
+		t.Errorf("Unexpected request %s with Prefer %q", gotQuery, gotPrefer)
+	}
+
+	if _, _, err := client.DeleteWhere(ctx, "sessions", DeleteGuard{MaxRows: 1}, Eq("user_id", "all")); !errors.Is(err, ErrTooManyRows) {
+		t.Errorf("Expected ErrTooManyRows, got %v", err)
+	}
+
+	if _, _, err := client.DeleteWhere(ctx, "sessions", DeleteGuard{AllowFullTable: true}); err != nil {
+		t.Errorf("Expected full-table delete to be allowed, got %v", err)
+	}
+	if gotQuery != "" || gotPrefer != "return=representation" {
+		t.Errorf("Unexpected request %s with Prefer %q", gotQuery, gotPrefer)
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	var gotQuery, gotPrefer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery, gotPrefer = r.URL.RawQuery, r.Header.Get("Prefer")
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		w.Write([]byte(`[{"id":2}]`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token")
+	ctx := context.Background()
+
+	body, err := client.Upsert(ctx, "Food", []byte(`[{"id":1},{"id":2}]`), UpsertOptions{OnConflict: []Column{"id"}, IgnoreDuplicates: true, Return: true})
+	if err != nil {
+		t.Fatalf("Upsert returned error: %v", err)
+	}
+	if string(body) != `[{"id":2}]` {
+		t.Errorf("Expected inserted rows, got %s", body)
+	}
+	if gotQuery != "on_conflict=id" || gotPrefer != "resolution=ignore-duplicates, return=representation" {
+		t.Errorf("Unexpected request %s with Prefer %q", gotQuery, gotPrefer)
+	}
+
+	if _, err := client.Upsert(ctx, "Food", []byte(`{"id":1}`), UpsertOptions{}); err != nil {
+		t.Fatalf("Upsert returned error: %v", err)
+	}
+	if gotQuery != "" || gotPrefer != "resolution=merge-duplicates" {
+		t.Errorf("Unexpected request %s with Prefer %q", gotQuery, gotPrefer)
+	}
+}
+
+func TestInsertColumns(t *testing.T) {
+	var gotQuery, gotPrefer string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery, gotPrefer = r.URL.RawQuery, r.Header.Get("Prefer")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token")
+	ctx := context.Background()
+
+	payload := []byte(`[{"id":1,"name":"a","tracking":{"x":1}}]`)
+	if _, err := client.Insert(ctx, "events", payload, "id", "name"); err != nil {
+		t.Fatalf("Insert returned error: %v", err)
+	}
+	if gotQuery != "columns=id%2Cname" {
+		t.Errorf("Expected columns=id,name, got %s", gotQuery)
+	}
+	if gotPrefer != "missing=default" {
+		t.Errorf("Expected Prefer: missing=default with columns, got %q", gotPrefer)
+	}
+	if _, err := client.WithRepresentation().Insert(ctx, "events", payload, "id"); err != nil || gotPrefer != "return=representation, missing=default" {
+		t.Errorf("Expected both preferences, got %q (%v)", gotPrefer, err)
+	}
+	if _, err := client.Insert(ctx, "events", payload); err != nil || gotQuery != "" || gotPrefer != "" {
+		t.Errorf("Expected no columns parameter or preference, got %s %q (%v)", gotQuery, gotPrefer, err)
+	}
+
+	writer := NewBulkWriter[map[string]any](client, "events")
+	writer.Columns = []Column{"id"}
+	if err := writer.Write(ctx, []map[string]any{{"id": 1, "extra": true}}); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if gotQuery != "columns=id" {
+		t.Errorf("Expected columns=id from BulkWriter, got %s", gotQuery)
+	}
+}
+
+func TestUpdateIfVersion(t *testing.T) {
+	version := 3
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Query().Get("id") != "eq.7" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("version") != "eq."+strconv.Itoa(version) {
+			w.Write([]byte(`[]`))
+			return
+		}
+		version++
+		w.Write([]byte(`[{"id":7,"version":` + strconv.Itoa(version) + `}]`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "key", "token")
+
+	row, err := client.UpdateIfVersion(context.Background(), "documents", Eq("id", 7), Eq("version", 3), []byte(`{"version":4}`))
+	if err != nil || string(row) != `{"id":7,"version":4}` {
+		t.Fatalf("Expected updated row, got %s and %v", row, err)
+	}
+	_, err = client.UpdateIfVersion(context.Background(), "documents", Eq("id", 7), Eq("version", 3), []byte(`{"version":4}`))
+	if !errors.Is(err, ErrStaleRow) {
+		t.Errorf("Expected ErrStaleRow for outdated version, got %v", err)
+	}
+}